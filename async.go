@@ -0,0 +1,104 @@
+package errstack
+
+import (
+	"context"
+)
+
+type asyncResult struct {
+	idx int
+	rp  *RecordingPutter
+	err error
+}
+
+// testHookAsyncResult is called when ValidateAsync receives a validator result.
+var testHookAsyncResult = func() {}
+
+// ValidateAsync runs validators concurrently and collects their request errors into
+// the builder. Validators put request errors using the Putter (which has the same key
+// space as `b` - use `Fork` to select a key) and return a non nil error only
+// in case of infrastructure failures (eg: database is not available).
+// Infrastructure errors are joined and returned, so they are not reported as
+// request errors.
+// ValidateAsync waits until all validators finish or the context is done. In the latter
+// case, errors of the unfinished validators are ignored and the context error is
+// returned as a Transient error. Panics of validators are recovered and returned
+// as Domain errors, like in Group.
+// Example:
+//
+//	err := ValidateAsync(ctx, errb,
+//		func(ctx context.Context, p Putter) error {
+//			taken, err := db.EmailTaken(ctx, obj.Email)
+//			if taken {
+//				p.Fork("email").Put("email is already used")
+//			}
+//			return err
+//		},
+//		...)
+//	if err != nil {
+//		return err
+//	}
+//	return errb.ToReqErr()
+func ValidateAsync(ctx context.Context, b Builder, validators ...func(context.Context, Putter) error) error {
+	var results = make(chan asyncResult, len(validators))
+	for i, v := range validators {
+		go func(i int, v func(context.Context, Putter) error) {
+			rp := NewRecordingPutter()
+			err := runValidator(ctx, v, rp)
+			results <- asyncResult{i, rp, err}
+		}(i, v)
+	}
+
+	var done = make([]*asyncResult, len(validators))
+	var ctxErr error
+loop:
+	for range validators {
+		select {
+		case r := <-results:
+			done[r.idx] = &r
+			testHookAsyncResult()
+		case <-ctx.Done():
+			ctxErr = Wrap(ctx.Err(), Transient, "validation aborted")
+			break loop
+		}
+	}
+	if ctxErr != nil { // collect validators which finished in the meantime
+		for {
+			select {
+			case r := <-results:
+				done[r.idx] = &r
+				continue
+			default:
+			}
+			break
+		}
+	}
+
+	// replay in the validators order to have a deterministic output
+	var errs []error
+	var root = b.Putter("")
+	for _, r := range done {
+		if r == nil {
+			continue
+		}
+		for _, rec := range r.rp.Records() {
+			p := root
+			for _, k := range rec.Path {
+				p = p.Fork(k)
+			}
+			p.Put(rec.Value)
+		}
+		if r.err != nil {
+			errs = append(errs, r.err)
+		}
+	}
+	return Join(append(errs, ctxErr)...)
+}
+
+func runValidator(ctx context.Context, v func(context.Context, Putter) error, p Putter) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = panicErr(r)
+		}
+	}()
+	return v(ctx, p)
+}
@@ -0,0 +1,74 @@
+package errstack
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/robert-zaremba/checkers"
+	. "gopkg.in/check.v1"
+)
+
+type AsyncSuite struct{}
+
+func (s *AsyncSuite) TestValidateAsync(c *C) {
+	b := NewBuilder()
+	errDB := errors.New("db is down")
+	err := ValidateAsync(context.Background(), b,
+		func(_ context.Context, p Putter) error {
+			p.Fork("email").Put("email is already used")
+			return nil
+		},
+		func(_ context.Context, p Putter) error {
+			p.Fork("address").Fork("city").Put("unknown city")
+			return errDB
+		},
+		func(_ context.Context, p Putter) error {
+			return nil
+		})
+	c.Assert(err, FitsTypeOf, joinedError{})
+	c.Check(err.(joinedError).errors, DeepEquals, []error{errDB})
+	c.Check(b.Get("email"), Equals, "email is already used")
+	c.Check(b.Get("address:city"), Equals, "unknown city")
+}
+
+func (s *AsyncSuite) TestValidateAsyncDeadline(c *C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// the context is done after the first result is received
+	testHookAsyncResult = cancel
+	defer func() { testHookAsyncResult = func() {} }()
+	var release = make(chan struct{})
+	defer close(release)
+	b := NewBuilder()
+	err := ValidateAsync(ctx, b,
+		func(_ context.Context, p Putter) error {
+			p.Fork("fast").Put("error")
+			return nil
+		},
+		func(_ context.Context, p Putter) error {
+			<-release
+			p.Fork("slow").Put("error")
+			return nil
+		})
+	c.Assert(err, NotNil)
+	c.Check(IsKind(Transient, err.(joinedError).errors[0]), IsTrue)
+	c.Check(b.Get("fast"), Equals, "error")
+	c.Check(b.Get("slow"), IsNil)
+}
+
+func (s *AsyncSuite) TestValidateAsyncPanic(c *C) {
+	b := NewBuilder()
+	err := ValidateAsync(context.Background(), b,
+		func(_ context.Context, p Putter) error {
+			panic("boom")
+		},
+		func(_ context.Context, p Putter) error {
+			p.Fork("email").Put("email is already used")
+			return nil
+		})
+	c.Assert(err, FitsTypeOf, joinedError{})
+	c.Check(err.(joinedError).errors, HasLen, 1)
+	c.Check(err.Error(), Matches, ".*panic: boom.*")
+	c.Check(IsKind(Domain, err.(joinedError).errors[0]), IsTrue)
+	c.Check(b.Get("email"), Equals, "email is already used")
+}
@@ -0,0 +1,208 @@
+package errstack
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Batch tracks results of a batch operation where every item can succeed or fail
+// independently. Request errors of an item are collected with a Builder returned by
+// `Item` (it's a `ForkIdx` of the batch builder), other errors are recorded with `Fail`.
+// Items without errors are considered successful.
+// Example:
+//
+//	var batch = NewBatch(len(objs))
+//	for i, o := range objs {
+//		validate(o, batch.Item(i))
+//		if !batch.IsFailed(i) {
+//			batch.Fail(i, store(o))
+//		}
+//	}
+//	w.WriteHeader(batch.StatusCode())
+//	json.NewEncoder(w).Encode(batch)
+//
+// Items with errors dropped because of the Builder limits are failed as well.
+// This structure is not thread safe.
+type Batch struct {
+	size int
+	b    builder
+	errs map[int][]error
+}
+
+// NewBatch creates a Batch for `size` items. Options are used to create
+// the underlying Builder.
+func NewBatch(size int, opts ...BuilderOption) *Batch {
+	return &Batch{size, NewBuilder(opts...).(builder), map[int][]error{}}
+}
+
+// Item returns a Builder for request errors of the item `idx`.
+// It panics if `idx` is out of range.
+func (bt *Batch) Item(idx int) Builder {
+	bt.checkIdx(idx)
+	return bt.b.ForkIdx(idx)
+}
+
+// Fail records an error of the item `idx`. Nil errors are ignored. All errors
+// of an item are kept and joined when marshaled.
+// Errors which are not request errors will be sanitized when marshaled. Request
+// errors of an item which has also Builder errors are rendered under the "" key.
+// It panics if `idx` is out of range.
+func (bt *Batch) Fail(idx int, err error) {
+	bt.checkIdx(idx)
+	if err != nil {
+		bt.errs[idx] = append(bt.errs[idx], err)
+	}
+}
+
+func (bt *Batch) checkIdx(idx int) {
+	if idx < 0 || idx >= bt.size {
+		panic(fmt.Sprintf("errstack: batch item index %d out of range [0,%d)", idx, bt.size))
+	}
+}
+
+// IsFailed checks if the item `idx` has any errors.
+func (bt *Batch) IsFailed(idx int) bool {
+	if len(bt.errs[idx]) != 0 || bt.dropped()[idx] != 0 {
+		return true
+	}
+	var prefix = strconv.Itoa(idx) + builderSep
+	for k := range bt.b.m {
+		if strings.HasPrefix(k, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// dropped returns the number of errors dropped because of the Builder limits by item.
+// It's derived from the Builder state, so it follows Rollback.
+func (bt *Batch) dropped() map[int]int {
+	var m = map[int]int{}
+	for _, k := range bt.b.s.dropped {
+		if idx, ok := batchIdx(k); ok {
+			m[idx]++
+		}
+	}
+	return m
+}
+
+// Counts returns the number of succeeded and failed items.
+func (bt *Batch) Counts() (succeeded, failed int) {
+	failed = len(bt.failures())
+	return bt.size - failed, failed
+}
+
+// StatusCode returns HTTP status code: 200 when all items succeeded and
+// 207 (Multi-Status) otherwise.
+func (bt *Batch) StatusCode() int {
+	if len(bt.failures()) == 0 {
+		return 200
+	}
+	return 207
+}
+
+type batchItem struct {
+	Index  int         `json:"index"`
+	Status int         `json:"status"`
+	Err    interface{} `json:"err"`
+}
+
+type batchSummary struct {
+	Total     int         `json:"total"`
+	Succeeded int         `json:"succeeded"`
+	Failed    int         `json:"failed"`
+	Truncated int         `json:"truncated,omitempty"`
+	Items     []batchItem `json:"items"`
+}
+
+// failures groups errors by item index. Infrastructure errors take precedence
+// over request errors of the same item. Request errors recorded with Fail are
+// merged with the Builder errors of the item under the "" key.
+func (bt *Batch) failures() map[int]batchItem {
+	var items = map[int]batchItem{}
+	var item = func(idx int) batchItem {
+		it, ok := items[idx]
+		if !ok {
+			it = batchItem{idx, 400, errmap{}}
+		}
+		return it
+	}
+	for k, v := range bt.b.m {
+		idx, ok := batchIdx(k)
+		if !ok {
+			continue
+		}
+		it := item(idx)
+		it.Err.(errmap)[k[strings.Index(k, builderSep)+1:]] = v
+		items[idx] = it
+	}
+	for idx, n := range bt.dropped() {
+		it := item(idx)
+		it.Err.(errmap)[TruncatedKey] = n
+		items[idx] = it
+	}
+	for idx, errs := range bt.errs {
+		var err = errs[0]
+		if len(errs) > 1 {
+			err = Join(errs...)
+		}
+		if it, ok := items[idx]; ok && isReqErr(err) {
+			it.Err.(errmap)[""] = err
+			continue
+		}
+		items[idx] = batchItem{idx, errStatusCode(err), sanitize(err)}
+	}
+	return items
+}
+
+// batchIdx returns the item index of a builder key.
+func batchIdx(key string) (int, bool) {
+	i := strings.Index(key, builderSep)
+	if i < 0 {
+		return 0, false
+	}
+	idx, err := strconv.Atoi(key[:i])
+	return idx, err == nil
+}
+
+// MarshalJSON implements Marshaller interface. It renders a summary with counts
+// and the list of failed items.
+func (bt *Batch) MarshalJSON() ([]byte, error) {
+	var failures = bt.failures()
+	var s = batchSummary{
+		Total:     bt.size,
+		Succeeded: bt.size - len(failures),
+		Failed:    len(failures),
+		Truncated: len(bt.b.s.dropped),
+		Items:     make([]batchItem, 0, len(failures)),
+	}
+	for _, item := range failures {
+		s.Items = append(s.Items, item)
+	}
+	sort.Slice(s.Items, func(i, j int) bool { return s.Items[i].Index < s.Items[j].Index })
+	return json.Marshal(s)
+}
+
+func isReqErr(err error) bool {
+	e, ok := err.(E)
+	return ok && e.IsReq()
+}
+
+func errStatusCode(err error) int {
+	if s, ok := err.(HasStatusCode); ok {
+		return s.StatusCode()
+	}
+	return 500
+}
+
+// sanitize returns a value which can be safely marshaled into a response.
+// E values sanitize themselves, other errors are not exposed.
+func sanitize(err error) interface{} {
+	if _, ok := err.(E); ok {
+		return err
+	}
+	return internalErrMsg
+}
@@ -0,0 +1,96 @@
+package errstack
+
+import (
+	"encoding/json"
+	"errors"
+
+	. "github.com/robert-zaremba/checkers"
+	. "gopkg.in/check.v1"
+)
+
+type BatchSuite struct{}
+
+func (s *BatchSuite) TestAllSucceeded(c *C) {
+	b := NewBatch(2)
+	b.Fail(0, nil)
+	c.Check(b.IsFailed(0), IsFalse)
+	c.Check(b.StatusCode(), Equals, 200)
+	data, err := json.Marshal(b)
+	c.Assert(err, IsNil)
+	c.Check(string(data), Equals, `{"total":2,"succeeded":2,"failed":0,"items":[]}`)
+}
+
+func (s *BatchSuite) TestPartialSuccess(c *C) {
+	b := NewBatch(4)
+	b.Item(0).Put("name", "too short")
+	b.Fail(2, NewIO("db connection: password=secret"))
+	b.Fail(3, errors.New("raw error"))
+
+	c.Check(b.IsFailed(0), IsTrue)
+	c.Check(b.IsFailed(1), IsFalse)
+	c.Check(b.IsFailed(2), IsTrue)
+	succeeded, failed := b.Counts()
+	c.Check(succeeded, Equals, 1)
+	c.Check(failed, Equals, 3)
+	c.Check(b.StatusCode(), Equals, 207)
+
+	data, err := json.Marshal(b)
+	c.Assert(err, IsNil)
+	c.Check(string(data), Equals, `{"total":4,"succeeded":1,"failed":3,"items":[`+
+		`{"index":0,"status":400,"err":{"name":"too short"}},`+
+		`{"index":2,"status":500,"err":{"incident":"42","msg":"Internal server error: db connection: password=secret"}},`+
+		`{"index":3,"status":500,"err":"Internal server error"}]}`)
+}
+
+func (s *BatchSuite) TestDroppedErrors(c *C) {
+	b := NewBatch(3, MaxErrors(1))
+	b.Item(0).Put("name", "too short")
+	b.Item(1).Put("name", "too short")
+	c.Check(b.IsFailed(1), IsTrue)
+	c.Check(b.IsFailed(2), IsFalse)
+	succeeded, failed := b.Counts()
+	c.Check(succeeded, Equals, 1)
+	c.Check(failed, Equals, 2)
+
+	data, err := json.Marshal(b)
+	c.Assert(err, IsNil)
+	c.Check(string(data), Equals, `{"total":3,"succeeded":1,"failed":2,"truncated":1,"items":[`+
+		`{"index":0,"status":400,"err":{"name":"too short"}},`+
+		`{"index":1,"status":400,"err":{"_truncated":1}}]}`)
+
+	// dropped errors are discarded by Rollback
+	b = NewBatch(2, MaxErrors(1))
+	b.Item(0).Put("name", "too short")
+	cp := b.Item(1).Checkpoint()
+	b.Item(1).Put("name", "too short")
+	c.Check(b.IsFailed(1), IsTrue)
+	b.Item(1).Rollback(cp)
+	c.Check(b.IsFailed(1), IsFalse)
+	_, failed = b.Counts()
+	c.Check(failed, Equals, 1)
+}
+
+func (s *BatchSuite) TestFail(c *C) {
+	b := NewBatch(2)
+	c.Check(func() { b.Fail(2, errors.New("x")) }, PanicMatches, `errstack: batch item index 2 out of range \[0,2\)`)
+	c.Check(func() { b.Item(-1) }, PanicMatches, `.*out of range.*`)
+
+	b.Fail(0, NewReq("invalid"))
+	b.Fail(0, NewIO("db"))
+	succeeded, failed := b.Counts()
+	c.Check(succeeded, Equals, 1)
+	c.Check(failed, Equals, 1)
+	data, err := json.Marshal(b)
+	c.Assert(err, IsNil)
+	c.Check(string(data), Equals, `{"total":2,"succeeded":1,"failed":1,"items":[`+
+		`{"index":0,"status":500,"err":{"errors":[{"msg":"invalid"},{"incident":"42","msg":"Internal server error: db"}],"incident":"42"}}]}`)
+
+	// request errors are merged with the item Builder errors
+	b = NewBatch(1)
+	b.Item(0).Put("name", "too short")
+	b.Fail(0, NewReq("email already used"))
+	data, err = json.Marshal(b)
+	c.Assert(err, IsNil)
+	c.Check(string(data), Equals, `{"total":1,"succeeded":0,"failed":1,"items":[`+
+		`{"index":0,"status":400,"err":{"":{"msg":"email already used"},"name":"too short"}}]}`)
+}
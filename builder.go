@@ -3,6 +3,7 @@ package errstack
 import (
 	"bytes"
 	"fmt"
+	"reflect"
 	"strconv"
 )
 
@@ -11,6 +12,10 @@ type errmap map[string]interface{}
 
 const builderSep = "|"
 
+// TruncatedKey is the key under which Builder reports the number of errors dropped
+// because of the MaxErrors or MaxErrorsPerKey limits.
+const TruncatedKey = "_truncated"
+
 // Builder is a type to incrementally build set of errors under common key structure
 // Builder intentionally doesn't implement standard Error interface. You have to explicitly
 // convert it into an Error (using ToReqErr) once all checks are done.
@@ -26,6 +31,10 @@ const builderSep = "|"
 //	}
 //	...
 //	return errb.ToReqErr()
+//
+// Simple checks can be written using the FieldChecker:
+//
+//	errb.Field("first_name", obj.Name).Required().MinLen(3)
 type Builder interface {
 	// Fork creates a new builder which shares the same space but all new added errors
 	// will be assigned to keys prefixed with `prefix`
@@ -39,12 +48,29 @@ type Builder interface {
 	// Puts new error under the key. You can put multiple errors under the same key
 	// and they will be agregated
 	Put(key string, value interface{})
+	// Field returns a fluent FieldChecker which puts errors under the key.
+	Field(key string, value interface{}) *FieldChecker
+	// Check puts `msg` under the key if `cond` is false. It returns `cond`.
+	Check(cond bool, key string, msg interface{}) bool
 	// Get returns errors under `key`. Get is aware about 'prefix' and it will add it
 	// to the the key.
 	Get(key string) interface{}
 
+	// Checkpoint returns a token which can be used to Rollback errors added after
+	// the checkpoint.
+	Checkpoint() Checkpoint
+	// Rollback discards all errors added after the checkpoint was created.
+	// Checkpoints are shared by all forks of the builder.
+	Rollback(Checkpoint)
+	// Try runs `f` and discards the errors it added if it reports a success.
+	// Errors are kept only if the alternative fails. It returns the `f` result.
+	Try(f func(Builder) bool) bool
+
 	// NotNil checks if there are any errors in the builder.
 	NotNil() bool
+	// Full checks if the builder reached the MaxErrors limit. Expensive validators
+	// can use it to stop early - all further errors will be dropped anyway.
+	Full() bool
 	// Converts the Builder into a request error.
 	ToReqErr() E
 	// ToList transforms Builder errors into a list
@@ -87,6 +113,19 @@ func (em errmap) Append(key string, value interface{}) {
 	}
 }
 
+// removeLast removes the last error stored under the key
+func (em errmap) removeLast(key string) {
+	ls, ok := em[key].(chain)
+	switch {
+	case !ok || len(ls) <= 1:
+		delete(em, key)
+	case len(ls) == 2:
+		em[key] = ls[0]
+	default:
+		em[key] = ls[:len(ls)-1]
+	}
+}
+
 var errmapSep = []byte(": ")
 
 // Error implements error interface
@@ -100,9 +139,50 @@ func (em errmap) Error() string {
 	return buffer.String()
 }
 
+// BuilderOption configures a Builder created with NewBuilder.
+type BuilderOption func(*builderOpts)
+
+type builderOpts struct {
+	maxErrors int
+	maxPerKey int
+	dedupe    bool
+}
+
+// MaxErrors limits the total number of errors a Builder keeps. Errors put after
+// the limit is reached are dropped and counted under TruncatedKey.
+func MaxErrors(n int) BuilderOption {
+	return func(o *builderOpts) { o.maxErrors = n }
+}
+
+// MaxErrorsPerKey limits the number of errors a Builder keeps under a single key.
+// Errors put after the limit is reached are dropped and counted under TruncatedKey.
+func MaxErrorsPerKey(n int) BuilderOption {
+	return func(o *builderOpts) { o.maxPerKey = n }
+}
+
+// DropDuplicates makes Builder ignore values which are already stored under the same key.
+func DropDuplicates() BuilderOption {
+	return func(o *builderOpts) { o.dedupe = true }
+}
+
+// builderState is shared between a builder and all its forks.
+type builderState struct {
+	opts    builderOpts
+	count   int        // number of stored errors
+	dropped []string   // keys of errors dropped because of limits
+	journal []ListNode // stored errors in the insertion order
+}
+
+// Checkpoint is a Builder state token created by Builder.Checkpoint.
+type Checkpoint struct {
+	n       int
+	dropped int
+}
+
 type builder struct {
 	m      errmap
 	prefix string
+	s      *builderState
 }
 
 func (b builder) Fork(prefix string) Builder {
@@ -110,7 +190,7 @@ func (b builder) Fork(prefix string) Builder {
 	if b.prefix != "" {
 		prefix = b.prefix + prefix
 	}
-	return builder{b.m, prefix}
+	return builder{b.m, prefix, b.s}
 }
 
 func (b builder) ForkIdx(idx int) Builder {
@@ -118,11 +198,110 @@ func (b builder) ForkIdx(idx int) Builder {
 }
 
 func (b builder) Put(key string, value interface{}) {
-	if value != nil {
-		b.m.Append(b.prefix+key, value)
+	if value == nil {
+		return
+	}
+	key = b.prefix + key
+	x, ok := b.m[key]
+	if ok && b.s.opts.dedupe && containsValue(x, value) {
+		return
+	}
+	if b.Full() || (ok && b.s.opts.maxPerKey > 0 && chainLen(x) >= b.s.opts.maxPerKey) {
+		b.s.dropped = append(b.s.dropped, key)
+		return
+	}
+	b.s.count++
+	b.s.journal = append(b.s.journal, ListNode{key, value})
+	b.m.Append(key, value)
+}
+
+func (b builder) Checkpoint() Checkpoint {
+	return Checkpoint{len(b.s.journal), len(b.s.dropped)}
+}
+
+func (b builder) Rollback(cp Checkpoint) {
+	for i := len(b.s.journal) - 1; i >= cp.n; i-- {
+		b.m.removeLast(b.s.journal[i].key)
+		b.s.count--
+	}
+	if cp.n <= len(b.s.journal) {
+		b.s.journal = b.s.journal[:cp.n]
+		b.s.dropped = b.s.dropped[:cp.dropped]
 	}
 }
 
+func (b builder) Try(f func(Builder) bool) bool {
+	cp := b.Checkpoint()
+	if f(b) {
+		b.Rollback(cp)
+		return true
+	}
+	return false
+}
+
+// OneOf validates union (oneOf) values. It tries alternatives in order and stops
+// on the first successful one. If all alternatives fail, only the errors of the best
+// matching alternative (the one with the least errors) are put into the builder.
+// Example:
+//
+//	OneOf(errb.Fork("payment"), validateCard(obj.Payment), validateTransfer(obj.Payment))
+func OneOf(b Builder, alternatives ...func(Builder) bool) bool {
+	var best *builderState
+	for _, alt := range alternatives {
+		tmp := NewBuilder().(builder)
+		if alt(tmp) {
+			return true
+		}
+		if best == nil || tmp.s.count < best.count {
+			best = tmp.s
+		}
+	}
+	if best != nil {
+		for _, n := range best.journal {
+			b.Put(n.key, n.val)
+		}
+	}
+	return false
+}
+
+// chainValues returns all values stored under a key.
+func chainValues(x interface{}) []interface{} {
+	if ls, ok := x.(chain); ok {
+		return ls
+	}
+	return []interface{}{x}
+}
+
+func chainLen(x interface{}) int {
+	if ls, ok := x.(chain); ok {
+		return len(ls)
+	}
+	return 1
+}
+
+func containsValue(x, value interface{}) bool {
+	if ls, ok := x.(chain); ok {
+		for _, v := range ls {
+			if reflect.DeepEqual(v, value) {
+				return true
+			}
+		}
+		return false
+	}
+	return reflect.DeepEqual(x, value)
+}
+
+func (b builder) Field(key string, value interface{}) *FieldChecker {
+	return NewFieldChecker(b.Putter(key), value)
+}
+
+func (b builder) Check(cond bool, key string, msg interface{}) bool {
+	if !cond {
+		b.Put(key, msg)
+	}
+	return cond
+}
+
 func (b builder) Get(key string) interface{} {
 	return b.m[b.prefix+key]
 }
@@ -131,13 +310,33 @@ func (b builder) NotNil() bool {
 	return len(b.m) > 0
 }
 
+func (b builder) Full() bool {
+	return b.s.opts.maxErrors > 0 && b.s.count >= b.s.opts.maxErrors
+}
+
 func (b builder) ToReqErr() E {
 	if b.NotNil() {
-		return newRequest(b.m, "", 1)
+		return newRequest(b.errmap(), "", 1)
 	}
 	return nil
 }
 
+// errmap returns a copy of builder errors, so errors returned by ToReqErr are not
+// changed by Put or Rollback. If some errors were dropped, it adds the truncation marker.
+func (b builder) errmap() errmap {
+	m := make(errmap, len(b.m)+1)
+	for k, v := range b.m {
+		if ls, ok := v.(chain); ok {
+			v = append(chain(nil), ls...)
+		}
+		m[k] = v
+	}
+	if len(b.s.dropped) != 0 {
+		m[TruncatedKey] = len(b.s.dropped)
+	}
+	return m
+}
+
 // ListNode is a representation used by Builder.ToList interface function
 type ListNode struct {
 	key string
@@ -148,11 +347,13 @@ func (b builder) ToList() []ListNode {
 	if !b.NotNil() {
 		return make([]ListNode, 0)
 	}
-	var l = make([]ListNode, len(b.m))
+	var m = b.errmap()
+	var l = make([]ListNode, len(m))
 	var i = 0
-	for k, v := range b.m {
+	for k, v := range m {
 		l[i].key = k
 		l[i].val = v
+		i++
 	}
 	return l
 }
@@ -162,9 +363,14 @@ func (b builder) Putter(key string) Putter {
 }
 
 // NewBuilder creates new builders with given prefix being appended to each error keys.
+// Options can limit the number of stored errors and drop duplicates.
 // This structure is not thread safe.
-func NewBuilder() Builder {
-	return builder{map[string]interface{}{}, ""}
+func NewBuilder(opts ...BuilderOption) Builder {
+	var s = &builderState{}
+	for _, o := range opts {
+		o(&s.opts)
+	}
+	return builder{map[string]interface{}{}, "", s}
 }
 
 type builderSetter struct {
@@ -44,3 +44,105 @@ func (s *BuilderSuite) TestFork(c *C) {
 	b := b1.(builder)
 	c.Assert(b.m, DeepEquals, errmap{"k": 1, "b|k": 2, "c|k": 3})
 }
+
+func (s *BuilderSuite) TestLimits(c *C) {
+	b := NewBuilder(MaxErrors(3), MaxErrorsPerKey(2))
+	b.Put("k1", 1)
+	b.Put("k1", 2)
+	b.Put("k1", 3) // dropped: per key limit
+	c.Check(b.Full(), IsFalse)
+	b.Fork("f").Put("k2", 4)
+	c.Check(b.Full(), IsTrue)
+	b.Put("k3", 5) // dropped: total limit
+
+	errR := b.ToReqErr().(*request)
+	c.Assert(errR.details, DeepEquals, errmap{"k1": chain{1, 2}, "f|k2": 4, TruncatedKey: 2})
+	c.Check(b.ToList(), HasLen, 3)
+	// truncation marker is not stored in the builder itself
+	c.Check(b.Get(TruncatedKey), IsNil)
+}
+
+func (s *BuilderSuite) TestDropDuplicates(c *C) {
+	b := NewBuilder(DropDuplicates())
+	b.Put("k", "a")
+	b.Put("k", "a")
+	b.Put("k", "b")
+	b.Put("k", "b")
+	c.Check(b.Get("k"), DeepEquals, chain{"a", "b"})
+
+	b = NewBuilder()
+	b.Put("k", "a")
+	b.Put("k", "a")
+	c.Check(b.Get("k"), DeepEquals, chain{"a", "a"})
+	c.Check(b.Full(), IsFalse)
+}
+
+func (s *BuilderSuite) TestCheckpoint(c *C) {
+	b := NewBuilder(MaxErrors(4))
+	b.Put("k", 1)
+	cp := b.Checkpoint()
+	b.Put("k", 2)
+	b.Put("k", 3)
+	b.Fork("f").Put("k", 4)
+	b.Put("x", 5) // dropped
+	c.Check(b.Full(), IsTrue)
+
+	b.Rollback(cp)
+	c.Check(b.Full(), IsFalse)
+	c.Assert(b.(builder).m, DeepEquals, errmap{"k": 1})
+	c.Check(b.ToReqErr().(*request).details, DeepEquals, errmap{"k": 1})
+
+	// rollback is idempotent
+	b.Rollback(cp)
+	c.Assert(b.(builder).m, DeepEquals, errmap{"k": 1})
+}
+
+func (s *BuilderSuite) TestToReqErrIsDetached(c *C) {
+	b := NewBuilder()
+	b.Put("a", 1)
+	b.Put("k", 1)
+	cp := b.Checkpoint()
+	b.Put("b", 2)
+	b.Put("k", 2)
+	b.Put("k", 3)
+	err := b.ToReqErr()
+
+	b.Rollback(cp)
+	b.Put("k", 4)
+	b.Put("k", 5)
+	c.Check(err.(*request).details, DeepEquals, errmap{"a": 1, "b": 2, "k": chain{1, 2, 3}})
+	c.Check(b.(builder).m, DeepEquals, errmap{"a": 1, "k": chain{1, 4, 5}})
+}
+
+func (s *BuilderSuite) TestTry(c *C) {
+	b := NewBuilder()
+	c.Check(b.Try(func(b Builder) bool {
+		b.Put("k", "warning")
+		return true
+	}), IsTrue)
+	c.Check(b.NotNil(), IsFalse)
+
+	c.Check(b.Try(func(b Builder) bool {
+		b.Put("k", "error")
+		return false
+	}), IsFalse)
+	c.Check(b.Get("k"), Equals, "error")
+}
+
+func (s *BuilderSuite) TestOneOf(c *C) {
+	var alt = func(errs ...string) func(Builder) bool {
+		return func(b Builder) bool {
+			for _, e := range errs {
+				b.Put("v", e)
+			}
+			return len(errs) == 0
+		}
+	}
+	b := NewBuilder()
+	c.Check(OneOf(b.Fork("p"), alt("a1", "a2"), alt("b1"), alt("c1", "c2")), IsFalse)
+	c.Assert(b.(builder).m, DeepEquals, errmap{"p|v": "b1"})
+
+	b = NewBuilder()
+	c.Check(OneOf(b, alt("a1"), alt()), IsTrue)
+	c.Check(b.NotNil(), IsFalse)
+}
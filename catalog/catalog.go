@@ -0,0 +1,202 @@
+/*
+Package catalog provides an error catalog: a file describing all error classes (codes)
+of an application. The catalog is used to generate errstack Sentinels and API docs
+(see cmd/errstackgen) and to check compatibility between catalog versions.
+
+Catalog can be written in YAML or JSON:
+
+	errors:
+	  - code: user_not_found
+	    kind: NotExist
+	    message: "user {id} not found"
+	    status: 404
+	    public: true
+
+Message placeholders have form `{name}` or `{name:type}`, where `type` is a Go type
+of the generated constructor argument (`interface{}` by default).
+*/
+package catalog
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/robert-zaremba/errstack"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Entry describes a single error class.
+type Entry struct {
+	Code    string `json:"code" yaml:"code"`
+	Kind    string `json:"kind" yaml:"kind"`
+	Message string `json:"message" yaml:"message"`
+	// Status overrides HTTP status code derived from the kind.
+	Status int `json:"status,omitempty" yaml:"status,omitempty"`
+	// Public errors are listed in the API docs and their messages are exposed
+	// to clients (see errstack.Sentinel.Public).
+	Public bool `json:"public" yaml:"public"`
+}
+
+// Catalog is a list of error classes.
+type Catalog struct {
+	Errors []Entry `json:"errors" yaml:"errors"`
+}
+
+// Param is a message template parameter.
+type Param struct {
+	Name string
+	Type string
+}
+
+var placeholderRe = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)(?::([^{}]+))?\}`)
+
+// Load reads a catalog file. Files with `.yaml` or `.yml` extension are decoded as
+// YAML, other files as JSON. The catalog is validated.
+func Load(path string) (*Catalog, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errstack.WrapAsIOf(err, "can't read catalog %s", path)
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	return Parse(data, ext == ".yaml" || ext == ".yml")
+}
+
+// Parse decodes and validates a YAML or JSON catalog.
+func Parse(data []byte, isYAML bool) (*Catalog, error) {
+	var c Catalog
+	var err error
+	if isYAML {
+		err = yaml.UnmarshalStrict(data, &c)
+	} else {
+		err = json.Unmarshal(data, &c)
+	}
+	if err != nil {
+		return nil, errstack.WrapAsReq(err, "can't decode catalog")
+	}
+	return &c, c.Validate()
+}
+
+// Validate checks if codes and Go names derived from them are unique and entries
+// are well defined.
+func (c *Catalog) Validate() error {
+	var errb = errstack.NewBuilder()
+	var codes = map[string]bool{}
+	var goNames = map[string]string{}
+	for i, e := range c.Errors {
+		b := errb.ForkIdx(i)
+		b.Field("code", e.Code).Required().Match(codeRe)
+		if codes[e.Code] {
+			b.Put("code", "duplicated code "+e.Code)
+		} else if other, ok := goNames[e.GoName()]; ok {
+			b.Put("code", "code "+e.Code+" has the same Go name as "+other)
+		}
+		codes[e.Code] = true
+		goNames[e.GoName()] = e.Code
+		if _, err := errstack.ParseKind(e.Kind); err != nil {
+			b.Put("kind", err.Error())
+		}
+		b.Field("message", e.Message).Required()
+		b.Check(!strings.ContainsAny(e.Message, "\r\n"), "message", "message can't contain new lines")
+		b.Field("status", e.Status).Max(599)
+		var params = map[string]bool{}
+		for _, p := range e.Params() {
+			b.Check(!params[p.Name], "message", "duplicated parameter "+p.Name)
+			params[p.Name] = true
+			b.Check(validParamName(p.Name, e), "message", "invalid parameter name "+p.Name)
+			b.Check(validParamType(p.Type), "message", "invalid type of parameter "+p.Name+": "+p.Type)
+		}
+	}
+	return errb.ToReqErr()
+}
+
+var codeRe = regexp.MustCompile(`^[a-z][a-z0-9_.]*$`)
+
+// validParamName checks if the parameter can be used as an argument name of the
+// generated constructors.
+func validParamName(name string, e Entry) bool {
+	return token.IsIdentifier(name) && name != "err" && name != "_" && name != "Err"+e.GoName()
+}
+
+// validParamType checks if the parameter type is a Go type expression which can
+// be used in the generated code. Only the errstack package is imported there.
+func validParamType(t string) bool {
+	expr, err := parser.ParseExpr(t)
+	if err != nil {
+		return false
+	}
+	switch expr.(type) {
+	case *ast.Ident, *ast.SelectorExpr, *ast.StarExpr, *ast.ArrayType, *ast.MapType,
+		*ast.ChanType, *ast.FuncType, *ast.InterfaceType, *ast.StructType:
+	default:
+		return false
+	}
+	var ok = true
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			if x, isIdent := n.X.(*ast.Ident); !isIdent || x.Name != "errstack" {
+				ok = false
+			}
+		case *ast.CallExpr, *ast.CompositeLit, *ast.FuncLit:
+			ok = false
+		}
+		return ok
+	})
+	return ok
+}
+
+// Find returns the entry with the given code.
+func (c *Catalog) Find(code string) (Entry, bool) {
+	for _, e := range c.Errors {
+		if e.Code == code {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Params returns the message template parameters.
+func (e Entry) Params() []Param {
+	var ps []Param
+	for _, m := range placeholderRe.FindAllStringSubmatch(e.Message, -1) {
+		t := strings.TrimSpace(m[2])
+		if t == "" {
+			t = "interface{}"
+		}
+		ps = append(ps, Param{m[1], t})
+	}
+	return ps
+}
+
+// Format returns the message template converted into a fmt format.
+func (e Entry) Format() string {
+	return placeholderRe.ReplaceAllString(strings.Replace(e.Message, "%", "%%", -1), "%v")
+}
+
+// StatusCode returns the HTTP status code of the entry errors.
+func (e Entry) StatusCode() int {
+	if e.Status != 0 {
+		return e.Status
+	}
+	k, _ := errstack.ParseKind(e.Kind)
+	if k.IsReq() {
+		return 400
+	}
+	return 500
+}
+
+// GoName returns the entry name used for Go identifiers, eg: `user_not_found` -> `UserNotFound`.
+func (e Entry) GoName() string {
+	var sb strings.Builder
+	for _, w := range strings.FieldsFunc(e.Code, func(r rune) bool { return r == '_' || r == '.' }) {
+		sb.WriteString(strings.ToUpper(w[:1]))
+		sb.WriteString(w[1:])
+	}
+	return sb.String()
+}
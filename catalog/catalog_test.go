@@ -0,0 +1,118 @@
+package catalog
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type CatalogSuite struct{}
+
+var _ = Suite(&CatalogSuite{})
+
+const catalogYAML = `
+errors:
+  - code: user_not_found
+    kind: NotExist
+    message: "user {id:string} not found"
+    status: 404
+    public: true
+  - code: db_failure
+    kind: IO
+    message: "database failure: 100%"
+  - code: quota.exceeded
+    kind: Permission
+    message: "quota {limit:int} exceeded for {user}"
+    public: true
+`
+
+func (s *CatalogSuite) TestParse(c *C) {
+	cat, err := Parse([]byte(catalogYAML), true)
+	c.Assert(err, IsNil)
+	c.Assert(cat.Errors, HasLen, 3)
+	e := cat.Errors[2]
+	c.Check(e.GoName(), Equals, "QuotaExceeded")
+	c.Check(e.Params(), DeepEquals, []Param{{"limit", "int"}, {"user", "interface{}"}})
+	c.Check(e.Format(), Equals, "quota %v exceeded for %v")
+	c.Check(e.StatusCode(), Equals, 400)
+	c.Check(cat.Errors[1].Format(), Equals, "database failure: 100%%")
+	c.Check(cat.Errors[1].StatusCode(), Equals, 500)
+
+	cat2, err := Parse([]byte(`{"errors":[{"code":"user_not_found","kind":"NotExist","message":"user {id:string} not found","status":404,"public":true}]}`), false)
+	c.Assert(err, IsNil)
+	c.Check(cat2.Errors[0], DeepEquals, cat.Errors[0])
+}
+
+func (s *CatalogSuite) TestValidate(c *C) {
+	_, err := Parse([]byte(`
+errors:
+  - code: a
+    kind: Bad
+    message: "{x} {x}"
+  - code: a
+    kind: IO
+  - code: user.not_found
+    kind: NotExist
+    message: "user not found"
+  - code: user_not_found
+    kind: NotExist
+    message: "user not found\n}\nfunc init() { panic(1) }"
+`), true)
+	c.Assert(err, NotNil)
+	msg := err.Error()
+	for _, m := range []string{`unknown error kind "Bad"`, "duplicated parameter x", "duplicated code a", "is required",
+		"code user_not_found has the same Go name as user.not_found", "message can't contain new lines"} {
+		c.Check(strings.Contains(msg, m), Equals, true, Commentf("%s not in %s", m, msg))
+	}
+}
+
+func (s *CatalogSuite) TestValidateParams(c *C) {
+	var check = func(message string, valid bool) {
+		cat := Catalog{Errors: []Entry{{Code: "user_not_found", Kind: "NotExist", Message: message}}}
+		c.Check(cat.Validate() == nil, Equals, valid, Commentf("%s", message))
+	}
+	check("{id:int64} {name:string} {ids:[]int} {m:map[string]*int} {e:errstack.E} {x}", true)
+	check("{b:[4]byte} {f:func(int) error} {c:chan<- int}", true)
+	check("{err}", false)
+	check("{func}", false)
+	check("{ErrUserNotFound}", false)
+	check("{d:time.Duration}", false)
+	check("{x:int)}", false)
+	check("{x:1+2}", false)
+	check("{x:len(a)}", false)
+}
+
+func (s *CatalogSuite) TestGenerateGo(c *C) {
+	cat, err := Parse([]byte(catalogYAML), true)
+	c.Assert(err, IsNil)
+	var buf bytes.Buffer
+	c.Assert(cat.GenerateGo(&buf, "users"), IsNil)
+	src := buf.String()
+	_, err = parser.ParseFile(token.NewFileSet(), "gen.go", src, 0)
+	c.Assert(err, IsNil)
+	for _, m := range []string{
+		`ErrUserNotFound = errstack.Define(errstack.NotExist, "user_not_found", "user %v not found").WithStatus(404).Public()`,
+		`func NewQuotaExceeded(limit int, user interface{}) errstack.E {`,
+		`return ErrQuotaExceeded.Wrap(err, limit, user)`,
+		`func WrapDbFailure(err error) errstack.E {`,
+	} {
+		c.Check(strings.Contains(src, m), Equals, true, Commentf("%s not in:\n%s", m, src))
+	}
+}
+
+func (s *CatalogSuite) TestDocs(c *C) {
+	cat, err := Parse([]byte(catalogYAML), true)
+	c.Assert(err, IsNil)
+	var buf bytes.Buffer
+	c.Assert(cat.WriteMarkdownDocs(&buf), IsNil)
+	c.Check(buf.String(), Equals, "| Code | HTTP status | Message |\n|------|-------------|---------|\n"+
+		"| `user_not_found` | 404 | user {id} not found |\n"+
+		"| `quota.exceeded` | 400 | quota {limit} exceeded for {user} |\n")
+	c.Check(cat.Docs()[1].Params, DeepEquals, []string{"limit", "user"})
+}
@@ -0,0 +1,119 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+
+	"github.com/robert-zaremba/errstack"
+)
+
+// Change describes a difference between two catalog versions.
+type Change struct {
+	Code     string
+	Breaking bool
+	Msg      string
+}
+
+func (c Change) String() string {
+	var level = "info"
+	if c.Breaking {
+		level = "BREAKING"
+	}
+	return fmt.Sprintf("%s: %s: %s", level, c.Code, c.Msg)
+}
+
+// Compare lists changes between the `from` and the `to` catalog version. Following changes
+// are breaking, because clients may depend on them:
+//
+// + removed code
+// + changed kind or HTTP status code
+// + removed message parameter
+// + public code which became private
+func Compare(from, to *Catalog) []Change {
+	var changes []Change
+	var add = func(code string, breaking bool, format string, a ...interface{}) {
+		changes = append(changes, Change{code, breaking, fmt.Sprintf(format, a...)})
+	}
+	for _, o := range from.Errors {
+		n, ok := to.Find(o.Code)
+		if !ok {
+			add(o.Code, true, "code removed")
+			continue
+		}
+		if o.Kind != n.Kind {
+			add(o.Code, true, "kind changed from %s to %s", o.Kind, n.Kind)
+		}
+		if o.StatusCode() != n.StatusCode() {
+			add(o.Code, true, "HTTP status changed from %d to %d", o.StatusCode(), n.StatusCode())
+		}
+		var params = map[string]bool{}
+		for _, p := range n.Params() {
+			params[p.Name] = true
+		}
+		for _, p := range o.Params() {
+			if !params[p.Name] {
+				add(o.Code, true, "message parameter %s removed", p.Name)
+			}
+		}
+		if o.Public && !n.Public {
+			add(o.Code, true, "code is not public any more")
+		}
+		if o.Message != n.Message {
+			add(o.Code, false, "message changed from %q to %q", o.Message, n.Message)
+		}
+	}
+	for _, n := range to.Errors {
+		if _, ok := from.Find(n.Code); !ok {
+			add(n.Code, false, "code added")
+		}
+	}
+	return changes
+}
+
+// HasBreaking checks if any of the changes is breaking.
+func HasBreaking(changes []Change) bool {
+	for _, c := range changes {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+var verbRe = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+// FromRegistry creates a catalog from all Sentinels defined in the current build
+// (see errstack.Sentinels). Message verbs are converted into positional parameters
+// (`{arg1}`, `{arg2}`...). A build can write the result with WriteJSON to compare
+// registered codes between builds.
+func FromRegistry() *Catalog {
+	var c = &Catalog{Errors: []Entry{}}
+	for _, s := range errstack.Sentinels() {
+		var n int
+		msg := verbRe.ReplaceAllStringFunc(s.Format(), func(v string) string {
+			if v == "%%" {
+				return "%"
+			}
+			n++
+			return "{arg" + strconv.Itoa(n) + "}"
+		})
+		c.Errors = append(c.Errors, Entry{
+			Code:    s.Code(),
+			Kind:    s.Kind().String(),
+			Message: msg,
+			Status:  s.Status(),
+			Public:  s.IsPublic(),
+		})
+	}
+	return c
+}
+
+// WriteJSON writes the catalog in the JSON catalog format.
+func (c *Catalog) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c)
+}
@@ -0,0 +1,67 @@
+package catalog
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/robert-zaremba/errstack"
+	. "gopkg.in/check.v1"
+)
+
+type CompatSuite struct{}
+
+var _ = Suite(&CompatSuite{})
+
+func (s *CompatSuite) TestCompare(c *C) {
+	old, err := Parse([]byte(catalogYAML), true)
+	c.Assert(err, IsNil)
+	c.Check(Compare(old, old), HasLen, 0)
+
+	to, err := Parse([]byte(`
+errors:
+  - code: user_not_found
+    kind: NotExist
+    message: "user {id:string} was not found"
+  - code: quota.exceeded
+    kind: Request
+    message: "quota exceeded for {user} {plan}"
+  - code: new_code
+    kind: IO
+    message: "new"
+`), true)
+	c.Assert(err, IsNil)
+	changes := Compare(old, to)
+	var l []string
+	for _, ch := range changes {
+		l = append(l, ch.String())
+	}
+	c.Check(l, DeepEquals, []string{
+		"BREAKING: user_not_found: HTTP status changed from 404 to 400",
+		"BREAKING: user_not_found: code is not public any more",
+		`info: user_not_found: message changed from "user {id:string} not found" to "user {id:string} was not found"`,
+		"BREAKING: db_failure: code removed",
+		"BREAKING: quota.exceeded: kind changed from Permission to Request",
+		"BREAKING: quota.exceeded: message parameter limit removed",
+		"BREAKING: quota.exceeded: code is not public any more",
+		`info: quota.exceeded: message changed from "quota {limit:int} exceeded for {user}" to "quota exceeded for {user} {plan}"`,
+		"info: new_code: code added",
+	})
+	c.Check(HasBreaking(changes), Equals, true)
+	c.Check(HasBreaking(changes[len(changes)-1:]), Equals, false)
+}
+
+var errTestRegistry = errstack.Define(errstack.NotExist, "catalog_test_registry", "%s: item %d not found (100%%)").WithStatus(404).Public()
+
+func (s *CompatSuite) TestFromRegistry(c *C) {
+	cat := FromRegistry()
+	e, ok := cat.Find(errTestRegistry.Code())
+	c.Assert(ok, Equals, true)
+	c.Check(e, DeepEquals, Entry{"catalog_test_registry", "NotExist", "{arg1}: item {arg2} not found (100%)", 404, true})
+
+	var buf bytes.Buffer
+	c.Assert(cat.WriteJSON(&buf), IsNil)
+	cat2, err := Parse(buf.Bytes(), false)
+	c.Assert(err, IsNil)
+	c.Check(cat2, DeepEquals, cat)
+	c.Check(strings.HasPrefix(buf.String(), "{\n  \"errors\": ["), Equals, true)
+}
@@ -0,0 +1,120 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+var goTmpl = template.Must(template.New("go").Funcs(template.FuncMap{
+	"quote": strconv.Quote,
+	"args":  goArgs,
+	"names": goNames,
+}).Parse(`// Code generated by errstackgen. DO NOT EDIT.
+
+package {{.Package}}
+
+import "github.com/robert-zaremba/errstack"
+
+// Error classes. Use errors.Is to check if an error belongs to a class.
+var (
+{{- range .Errors}}
+	// Err{{.GoName}}: {{.Message}}
+	Err{{.GoName}} = errstack.Define(errstack.{{.Kind}}, {{quote .Code}}, {{quote .Format}}){{if .Status}}.WithStatus({{.Status}}){{end}}{{if .Public}}.Public(){{end}}
+{{- end}}
+)
+{{range .Errors}}
+// New{{.GoName}} creates {{.Code}} error.
+func New{{.GoName}}({{args .Params}}) errstack.E {
+	return Err{{.GoName}}.New({{names .Params}})
+}
+
+// Wrap{{.GoName}} creates {{.Code}} error wrapping err. It returns nil if err is nil.
+func Wrap{{.GoName}}(err error{{if .Params}}, {{args .Params}}{{end}}) errstack.E {
+	return Err{{.GoName}}.Wrap(err{{if .Params}}, {{names .Params}}{{end}})
+}
+{{end}}`))
+
+func goArgs(ps []Param) string {
+	var l = make([]string, len(ps))
+	for i, p := range ps {
+		l[i] = p.Name + " " + p.Type
+	}
+	return strings.Join(l, ", ")
+}
+
+func goNames(ps []Param) string {
+	var l = make([]string, len(ps))
+	for i, p := range ps {
+		l[i] = p.Name
+	}
+	return strings.Join(l, ", ")
+}
+
+// GenerateGo writes Go code with errstack Sentinels and typed constructors for
+// all catalog entries.
+func (c *Catalog) GenerateGo(w io.Writer, pkg string) error {
+	var buf bytes.Buffer
+	err := goTmpl.Execute(&buf, struct {
+		Package string
+		*Catalog
+	}{pkg, c})
+	if err != nil {
+		return err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("can't format generated code: %v", err)
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+// DocEntry is a public catalog entry written to API docs.
+type DocEntry struct {
+	Code    string   `json:"code"`
+	Kind    string   `json:"kind"`
+	Status  int      `json:"status"`
+	Message string   `json:"message"`
+	Params  []string `json:"params,omitempty"`
+}
+
+// Docs returns public entries for API docs.
+func (c *Catalog) Docs() []DocEntry {
+	var docs = []DocEntry{}
+	for _, e := range c.Errors {
+		if !e.Public {
+			continue
+		}
+		msg := placeholderRe.ReplaceAllString(e.Message, "{$1}") // hide parameter types
+		d := DocEntry{e.Code, e.Kind, e.StatusCode(), msg, nil}
+		for _, p := range e.Params() {
+			d.Params = append(d.Params, p.Name)
+		}
+		docs = append(docs, d)
+	}
+	return docs
+}
+
+// WriteJSONDocs writes public entries as JSON.
+func (c *Catalog) WriteJSONDocs(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c.Docs())
+}
+
+// WriteMarkdownDocs writes public entries as a markdown table.
+func (c *Catalog) WriteMarkdownDocs(w io.Writer) error {
+	var buf bytes.Buffer
+	buf.WriteString("| Code | HTTP status | Message |\n|------|-------------|---------|\n")
+	for _, d := range c.Docs() {
+		fmt.Fprintf(&buf, "| `%s` | %d | %s |\n", d.Code, d.Status, strings.Replace(d.Message, "|", `\|`, -1))
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
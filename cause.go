@@ -0,0 +1,74 @@
+package errstack
+
+// children returns errors wrapped by err: members of joined errors, the result
+// of `Unwrap` or the `Cause` of HasUnderlying.
+func children(err error) []error {
+	if u, ok := err.(interface{ Unwrap() []error }); ok {
+		return u.Unwrap()
+	}
+	if next := unwrapOnce(err); next != nil {
+		return []error{next}
+	}
+	return nil
+}
+
+// Walk traverses the err tree depth first and calls `fn` for every node: layers
+// created by Wrap and WithMsg, causes and members of joined errors. `depth` is 0 for
+// err and increases with every level. The walk stops when `fn` returns false.
+// Use LayerOf to inspect the node.
+func Walk(err error, fn func(depth int, e error) bool) {
+	walk(err, 0, fn)
+}
+
+func walk(err error, depth int, fn func(int, error) bool) bool {
+	if err == nil {
+		return true
+	}
+	if !fn(depth, err) {
+		return false
+	}
+	for _, c := range children(err) {
+		if !walk(c, depth+1, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// Chain returns all nodes of the err tree in the Walk order.
+func Chain(err error) []error {
+	var errs []error
+	Walk(err, func(_ int, e error) bool {
+		errs = append(errs, e)
+		return true
+	})
+	return errs
+}
+
+// FindCause returns the first error in the err tree for which `match` returns true.
+// The tree is traversed depth first, using `Cause`, `Unwrap` and members of joined
+// errors. It returns nil if no error matches.
+func FindCause(err error, match func(error) bool) (found error) {
+	Walk(err, func(_ int, e error) bool {
+		if match(e) {
+			found = e
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// AsType returns the first error in the err tree of type T (see FindCause).
+// Example:
+//
+//	if urlErr, ok := errstack.AsType[*url.Error](err); ok && urlErr.Timeout() { ... }
+func AsType[T error](err error) (T, bool) {
+	var t T
+	var ok bool
+	FindCause(err, func(e error) bool {
+		t, ok = e.(T)
+		return ok
+	})
+	return t, ok
+}
@@ -0,0 +1,110 @@
+package errstack
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	. "github.com/robert-zaremba/checkers"
+	. "gopkg.in/check.v1"
+)
+
+type CauseSuite struct{}
+
+type testCodeErr struct{ code int }
+
+func (e *testCodeErr) Error() string { return fmt.Sprint("code ", e.code) }
+
+func (s *CauseSuite) TestAsType(c *C) {
+	urlErr := &url.Error{Op: "Get", URL: "http://x", Err: errors.New("timeout")}
+	err := WrapAsReq(WrapAsIO(urlErr, "can't fetch"), "invalid url")
+
+	found, ok := AsType[*url.Error](err)
+	c.Check(ok, IsTrue)
+	c.Check(found, Equals, urlErr)
+
+	_, ok = AsType[*testCodeErr](err)
+	c.Check(ok, IsFalse)
+	_, ok = AsType[*url.Error](nil)
+	c.Check(ok, IsFalse)
+
+	req, ok := AsType[*request](WrapAsIO(NewReqDetails("k", "v", ""), "wrapped"))
+	c.Check(ok, IsTrue)
+	c.Check(req.details, DeepEquals, errmap{"k": "v"})
+
+	// joined errors and multi %w
+	codeErr := &testCodeErr{2}
+	joined := fmt.Errorf("a: %w, b: %w", errors.New("a"), Join(NewIO("io"), WrapAsDomain(codeErr, "x")))
+	found2, ok := AsType[*testCodeErr](joined)
+	c.Check(ok, IsTrue)
+	c.Check(found2, Equals, codeErr)
+}
+
+func (s *CauseSuite) TestFindCause(c *C) {
+	inner := NewReq("inner")
+	err := Join(errors.New("first"), WrapAsDomain(fmt.Errorf("wrapped: %w", inner), "outer"))
+	found := FindCause(err, func(e error) bool { return IsKind(Request, e) })
+	c.Check(found, Equals, inner)
+	c.Check(FindCause(err, func(error) bool { return false }), IsNil)
+	c.Check(FindCause(nil, func(error) bool { return true }), IsNil)
+}
+
+func (s *CauseSuite) TestWalk(c *C) {
+	base := errors.New("base")
+	io := WrapAsIO(base, "io").WithMsg("retry")
+	req := NewReqDetails("k", "v", "bad")
+	err := Join(io, req)
+
+	type node struct {
+		depth int
+		msg   string
+	}
+	var nodes []node
+	Walk(err, func(depth int, e error) bool {
+		nodes = append(nodes, node{depth, LayerOf(e).Msg})
+		return true
+	})
+	c.Check(nodes, DeepEquals, []node{
+		{0, ""}, {1, "retry"}, {2, "io"}, {3, "base"}, {1, "bad"}})
+
+	nodes = nil
+	Walk(err, func(depth int, e error) bool {
+		nodes = append(nodes, node{depth, LayerOf(e).Msg})
+		return depth < 2
+	})
+	c.Check(nodes, HasLen, 3)
+
+	c.Check(Chain(io), HasLen, 3)
+	c.Check(Chain(nil), HasLen, 0)
+	Walk(nil, func(int, error) bool {
+		c.Error("nil errors must not be visited")
+		return true
+	})
+}
+
+func (s *CauseSuite) TestLayerOf(c *C) {
+	err := WrapAsIO(errors.New("base"), "io").With("user", 1).WithMsg("retry")
+	layers := Chain(err)
+	c.Assert(layers, HasLen, 3)
+
+	l := LayerOf(layers[0])
+	c.Check(l.Kind, Equals, IO)
+	c.Check(l.Msg, Equals, "retry")
+	c.Check(l.Details, DeepEquals, map[string]interface{}{"user": 1})
+	c.Check(l.Stack, NotNil)
+
+	l = LayerOf(layers[1])
+	c.Check(l.Kind, Equals, IO)
+	c.Check(l.Msg, Equals, "io")
+	c.Check(l.Stack, IsNil)
+
+	l = LayerOf(layers[2])
+	c.Check(l.Kind, Equals, Other)
+	c.Check(l.Msg, Equals, "base")
+	c.Check(l.Details, HasLen, 0)
+
+	l = LayerOf(NewReqDetails("k", "v", "bad").With("x", 2))
+	c.Check(l.Kind, Equals, Request)
+	c.Check(l.Msg, Equals, "bad")
+	c.Check(l.Details, DeepEquals, map[string]interface{}{"k": "v", "x": 2})
+}
@@ -0,0 +1,242 @@
+package errstack
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"time"
+	"unicode/utf8"
+)
+
+// FieldChecker is a fluent validator of a single value. Every failed check puts an
+// error using the underlying Putter. After a failed Required check all other checks
+// are skipped.
+// Example:
+//
+//	errb.Field("name", obj.Name).Required().MinLen(3).Match(nameRe)
+//	errb.Field("age", obj.Age).Min(18)
+//	errb.Check(obj.Start.Before(obj.End), "end", "must be after start")
+//
+// Pointers are dereferenced. A nil pointer is a missing value: only Required and
+// Assert report errors for it, so optional fields can be modelled with pointers.
+// A non-nil pointer is present, even if it points to a zero value.
+// Using a check with a value of not supported type is a programming error and panics.
+// Use typed entry points (String, Number, Time, Slice, Map) to check value types
+// at compile time.
+type FieldChecker struct {
+	p    Putter
+	v    reflect.Value
+	ptr  bool // value was passed as a non-nil pointer
+	stop bool
+}
+
+// NewFieldChecker creates a FieldChecker for the value which puts errors using `p`.
+func NewFieldChecker(p Putter, value interface{}) *FieldChecker {
+	var v = reflect.ValueOf(value)
+	var ptr bool
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v, ptr = reflect.Value{}, false
+			break
+		}
+		v, ptr = v.Elem(), true
+	}
+	return &FieldChecker{p, v, ptr, false}
+}
+
+// Numeric is a constraint of number types supported by Number.
+type Numeric interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~uintptr | ~float32 | ~float64
+}
+
+// String creates a FieldChecker for a string value.
+func String[T ~string](p Putter, v T) *FieldChecker {
+	return NewFieldChecker(p, v)
+}
+
+// Number creates a FieldChecker for a number.
+func Number[T Numeric](p Putter, v T) *FieldChecker {
+	return NewFieldChecker(p, v)
+}
+
+// Time creates a FieldChecker for a time value.
+func Time(p Putter, v time.Time) *FieldChecker {
+	return NewFieldChecker(p, v)
+}
+
+// Slice creates a FieldChecker for a slice.
+func Slice[T any](p Putter, v []T) *FieldChecker {
+	return NewFieldChecker(p, v)
+}
+
+// Map creates a FieldChecker for a map.
+func Map[K comparable, V any](p Putter, v map[K]V) *FieldChecker {
+	return NewFieldChecker(p, v)
+}
+
+// Assert puts `msg` if `cond` is false.
+func (c *FieldChecker) Assert(cond bool, msg interface{}) *FieldChecker {
+	if !c.stop && !cond {
+		c.p.Put(msg)
+	}
+	return c
+}
+
+// Required checks if the value is not a zero value (empty string, collection, nil...).
+// Values passed as non-nil pointers are not checked for zero values.
+// Following checks are skipped if the value is missing.
+func (c *FieldChecker) Required() *FieldChecker {
+	if c.stop {
+		return c
+	}
+	if !c.v.IsValid() || !c.ptr && isZero(c.v) {
+		c.p.Put("is required")
+		c.stop = true
+	}
+	return c
+}
+
+// MinLen checks if the length of a string (in characters), slice, array or map is at least `n`.
+func (c *FieldChecker) MinLen(n int) *FieldChecker {
+	if l, ok := c.len("MinLen"); ok && l < n {
+		c.p.Put(fmt.Sprintf("must have at least %d %s", n, c.lenUnit()))
+	}
+	return c
+}
+
+// MaxLen checks if the length of a string (in characters), slice, array or map is at most `n`.
+func (c *FieldChecker) MaxLen(n int) *FieldChecker {
+	if l, ok := c.len("MaxLen"); ok && l > n {
+		c.p.Put(fmt.Sprintf("must have at most %d %s", n, c.lenUnit()))
+	}
+	return c
+}
+
+// Match checks if a string matches the regular expression.
+func (c *FieldChecker) Match(re *regexp.Regexp) *FieldChecker {
+	if c.stop || !c.v.IsValid() {
+		return c
+	}
+	if c.v.Kind() != reflect.String {
+		return c.invalidType("Match")
+	}
+	if !re.MatchString(c.v.String()) {
+		c.p.Put("has invalid format")
+	}
+	return c
+}
+
+// Min checks if a number is greater or equal to `x`.
+func (c *FieldChecker) Min(x float64) *FieldChecker {
+	if f, ok := c.number("Min"); ok && f < x {
+		c.p.Put(fmt.Sprintf("must be at least %v", x))
+	}
+	return c
+}
+
+// Max checks if a number is less or equal to `x`.
+func (c *FieldChecker) Max(x float64) *FieldChecker {
+	if f, ok := c.number("Max"); ok && f > x {
+		c.p.Put(fmt.Sprintf("must be at most %v", x))
+	}
+	return c
+}
+
+// Before checks if a time.Time value is before `t`.
+func (c *FieldChecker) Before(t time.Time) *FieldChecker {
+	if v, ok := c.time("Before"); ok && !v.Before(t) {
+		c.p.Put("must be before " + t.Format(time.RFC3339))
+	}
+	return c
+}
+
+// After checks if a time.Time value is after `t`.
+func (c *FieldChecker) After(t time.Time) *FieldChecker {
+	if v, ok := c.time("After"); ok && !v.After(t) {
+		c.p.Put("must be after " + t.Format(time.RFC3339))
+	}
+	return c
+}
+
+// OneOf checks if the value is equal to one of `values`.
+func (c *FieldChecker) OneOf(values ...interface{}) *FieldChecker {
+	if c.stop || !c.v.IsValid() {
+		return c
+	}
+	var v = c.v.Interface()
+	for _, x := range values {
+		if reflect.DeepEqual(v, x) {
+			return c
+		}
+	}
+	c.p.Put(fmt.Sprintf("must be one of %v", values))
+	return c
+}
+
+func (c *FieldChecker) invalidType(check string) *FieldChecker {
+	panic(fmt.Sprintf("errstack: %s check doesn't support %s values", check, c.v.Type()))
+}
+
+func (c *FieldChecker) len(check string) (int, bool) {
+	if c.stop || !c.v.IsValid() {
+		return 0, false
+	}
+	switch c.v.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(c.v.String()), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return c.v.Len(), true
+	}
+	c.invalidType(check)
+	return 0, false
+}
+
+func (c *FieldChecker) lenUnit() string {
+	if c.v.Kind() == reflect.String {
+		return "characters"
+	}
+	return "elements"
+}
+
+func (c *FieldChecker) number(check string) (float64, bool) {
+	if c.stop || !c.v.IsValid() {
+		return 0, false
+	}
+	switch c.v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(c.v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(c.v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return c.v.Float(), true
+	}
+	c.invalidType(check)
+	return 0, false
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (c *FieldChecker) time(check string) (time.Time, bool) {
+	if c.stop || !c.v.IsValid() {
+		return time.Time{}, false
+	}
+	if c.v.Type() == timeType {
+		return c.v.Interface().(time.Time), true
+	}
+	c.invalidType(check)
+	return time.Time{}, false
+}
+
+func isZero(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).IsZero()
+	}
+	return v.IsZero()
+}
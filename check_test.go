@@ -0,0 +1,106 @@
+package errstack
+
+import (
+	"regexp"
+	"time"
+
+	. "github.com/robert-zaremba/checkers"
+	. "gopkg.in/check.v1"
+)
+
+type CheckSuite struct{}
+
+func (s *CheckSuite) TestStrings(c *C) {
+	b := NewBuilder()
+	re := regexp.MustCompile("^[a-z]+$")
+	b.Field("empty", "").Required().MinLen(3).Match(re)
+	b.Field("short", "A").Required().MinLen(3).Match(re)
+	b.Field("ok", "żółw").Required().MinLen(3).MaxLen(4).Match(regexp.MustCompile(".+"))
+	b.Field("optional", "").MaxLen(3)
+
+	c.Check(b.Get("empty"), Equals, "is required")
+	c.Check(b.Get("short"), DeepEquals, chain{"must have at least 3 characters", "has invalid format"})
+	c.Check(b.Get("ok"), IsNil)
+	c.Check(b.Get("optional"), IsNil)
+}
+
+func (s *CheckSuite) TestNumbersAndCollections(c *C) {
+	b := NewBuilder()
+	b.Field("age", 12).Min(18).Max(100)
+	b.Field("price", 10.5).Min(0).Max(10)
+	b.Field("tags", []string{"a", "b"}).MaxLen(1)
+	b.Field("attrs", map[string]int{}).Required()
+	b.Field("color", "red").OneOf("green", "blue")
+	b.Field("count", uint(3)).Required().OneOf(uint(3))
+
+	c.Check(b.Get("age"), Equals, "must be at least 18")
+	c.Check(b.Get("price"), Equals, "must be at most 10")
+	c.Check(b.Get("tags"), Equals, "must have at most 1 elements")
+	c.Check(b.Get("attrs"), Equals, "is required")
+	c.Check(b.Get("color"), Equals, "must be one of [green blue]")
+	c.Check(b.Get("count"), IsNil)
+}
+
+func (s *CheckSuite) TestTimes(c *C) {
+	b := NewBuilder()
+	t := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	b.Field("start", t).Required().Before(t.Add(time.Hour)).After(t)
+	b.Field("end", time.Time{}).Required().After(t)
+
+	c.Check(b.Get("start"), Equals, "must be after 2020-01-01T00:00:00Z")
+	c.Check(b.Get("end"), Equals, "is required")
+}
+
+func (s *CheckSuite) TestCheckAndInvalidType(c *C) {
+	b := NewBuilder()
+	c.Check(b.Check(false, "k", "failed"), IsFalse)
+	c.Check(b.Check(true, "k", "ok"), IsTrue)
+	c.Check(b.Get("k"), Equals, "failed")
+
+	c.Check(func() { NewFieldChecker(b.Putter("n"), 1).MinLen(2) }, PanicMatches,
+		"errstack: MinLen check doesn't support int values")
+	c.Check(func() { b.Field("t", "x").Before(time.Now()) }, PanicMatches, ".*Before check.*")
+	c.Check(b.Get("n"), IsNil)
+}
+
+func (s *CheckSuite) TestPointers(c *C) {
+	b := NewBuilder()
+	var name = "A"
+	var missing *string
+	b.Field("name", &name).Required().MinLen(2)
+	b.Field("optional", missing).MinLen(2).Match(regexp.MustCompile("x")).Min(1).After(time.Now())
+	b.Field("required", missing).Required()
+	c.Check(b.Get("name"), Equals, "must have at least 2 characters")
+	c.Check(b.Get("optional"), IsNil)
+	c.Check(b.Get("required"), Equals, "is required")
+
+	// pointers to zero values are present
+	var qty, flag, empty = 0, false, ""
+	b.Field("qty", &qty).Required().Min(1)
+	b.Field("flag", &flag).Required()
+	b.Field("empty", &empty).Required()
+	var pp = &empty
+	b.Field("pp", &pp).Required()
+	c.Check(b.Get("qty"), Equals, "must be at least 1")
+	c.Check(b.Get("flag"), IsNil)
+	c.Check(b.Get("empty"), IsNil)
+	c.Check(b.Get("pp"), IsNil)
+}
+
+type testLevel uint8
+
+func (s *CheckSuite) TestTyped(c *C) {
+	b := NewBuilder()
+	String(b.Putter("name"), "ab").MinLen(3)
+	Number(b.Putter("level"), testLevel(3)).Max(2)
+	Time(b.Putter("start"), time.Time{}).Required()
+	Slice(b.Putter("tags"), []string{}).Required()
+	Map(b.Putter("attrs"), map[string]int{"a": 1}).MaxLen(0)
+	c.Check(b.ToReqErr().Details(), DeepEquals, map[string]interface{}{
+		"name":  "must have at least 3 characters",
+		"level": "must be at most 2",
+		"start": "is required",
+		"tags":  "is required",
+		"attrs": "must have at most 0 elements",
+	})
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+var dumpTmpl = template.Must(template.New("dump").Parse(`package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/robert-zaremba/errstack/catalog"
+{{range .}}
+	_ {{printf "%q" .}}
+{{- end}}
+)
+
+func main() {
+	if err := catalog.FromRegistry().WriteJSON(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`))
+
+// dump writes the JSON catalog of codes registered by the packages (see
+// catalog.FromRegistry). It builds and runs a program importing the packages,
+// so it must be run inside the module which contains them.
+func dump(patterns []string, w io.Writer) error {
+	pkgs, err := listPackages(patterns)
+	if err != nil {
+		return err
+	}
+	dir, err := os.MkdirTemp("", "errstackcompat")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	var src bytes.Buffer
+	if err = dumpTmpl.Execute(&src, pkgs); err != nil {
+		return err
+	}
+	var file = filepath.Join(dir, "main.go")
+	if err = os.WriteFile(file, src.Bytes(), 0o600); err != nil {
+		return err
+	}
+	cmd := exec.Command("go", "run", file)
+	cmd.Stdout = w
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// listPackages resolves package patterns into import paths. Main packages can't
+// be imported, so they are skipped.
+func listPackages(patterns []string) ([]string, error) {
+	args := append([]string{"list", "-f", `{{if ne .Name "main"}}{{.ImportPath}}{{end}}`}, patterns...)
+	cmd := exec.Command("go", args...)
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("can't list packages: %v", err)
+	}
+	return strings.Fields(string(out)), nil
+}
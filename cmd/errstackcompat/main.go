@@ -0,0 +1,70 @@
+// Command errstackcompat compares two versions of an error catalog and reports
+// breaking changes (see catalog.Compare). It exits with status 1 if there are
+// breaking changes, so it can be used in a pre-merge pipeline:
+//
+//	errstackcompat old/errors.yaml errors.yaml
+//
+// To compare codes registered in two builds, dump them with the `-dump` flag
+// (run inside the module, for every build) and compare the JSON files:
+//
+//	errstackcompat -dump ./... > new.json
+//	git worktree add ../base main && (cd ../base && errstackcompat -dump ./...) > old.json
+//	errstackcompat old.json new.json
+//
+// The dump builds and runs a program importing the packages (main packages are
+// skipped) which writes codes of all Sentinels (see catalog.FromRegistry).
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/robert-zaremba/errstack/catalog"
+)
+
+func main() {
+	var (
+		quiet   = flag.Bool("q", false, "report only breaking changes")
+		dumpReg = flag.Bool("dump", false, "write the JSON catalog of codes registered by the packages")
+	)
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: errstackcompat [-q] <old catalog> <new catalog>")
+		fmt.Fprintln(os.Stderr, "       errstackcompat -dump <packages>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *dumpReg {
+		if flag.NArg() == 0 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		exitOnErr(dump(flag.Args(), os.Stdout))
+		return
+	}
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	from, err := catalog.Load(flag.Arg(0))
+	exitOnErr(err)
+	to, err := catalog.Load(flag.Arg(1))
+	exitOnErr(err)
+
+	changes := catalog.Compare(from, to)
+	for _, c := range changes {
+		if c.Breaking || !*quiet {
+			fmt.Println(c)
+		}
+	}
+	if catalog.HasBreaking(changes) {
+		os.Exit(1)
+	}
+}
+
+func exitOnErr(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "errstackcompat:", err)
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,69 @@
+// Command errstackgen generates errstack Sentinels, typed constructors and API docs
+// from an error catalog (see the catalog package for the file format).
+// Usage with go generate:
+//
+//	//go:generate errstackgen -catalog errors.yaml -out errors_gen.go -md ERRORS.md
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/robert-zaremba/errstack/catalog"
+)
+
+func main() {
+	var (
+		in      = flag.String("catalog", "", "catalog file (YAML or JSON)")
+		pkg     = flag.String("pkg", os.Getenv("GOPACKAGE"), "package name of the generated code")
+		out     = flag.String("out", "", "output Go file (stdout if empty)")
+		mdOut   = flag.String("md", "", "output markdown docs file")
+		jsonOut = flag.String("json", "", "output JSON docs file")
+	)
+	flag.Parse()
+	if *in == "" || *pkg == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(*in, *pkg, *out, *mdOut, *jsonOut); err != nil {
+		fmt.Fprintln(os.Stderr, "errstackgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(in, pkg, out, mdOut, jsonOut string) error {
+	c, err := catalog.Load(in)
+	if err != nil {
+		return err
+	}
+	if err = writeFile(out, func(w io.Writer) error { return c.GenerateGo(w, pkg) }); err != nil {
+		return err
+	}
+	if mdOut != "" {
+		if err = writeFile(mdOut, c.WriteMarkdownDocs); err != nil {
+			return err
+		}
+	}
+	if jsonOut != "" {
+		err = writeFile(jsonOut, c.WriteJSONDocs)
+	}
+	return err
+}
+
+// writeFile writes to the file `name` or to stdout if name is empty.
+func writeFile(name string, write func(io.Writer) error) error {
+	if name == "" {
+		return write(os.Stdout)
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err = write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,63 @@
+package errstack
+
+import (
+	"sort"
+	"sync"
+)
+
+var codes = struct {
+	sync.Mutex
+	m         map[string]bool
+	sentinels []*Sentinel
+}{m: map[string]bool{}}
+
+// RegisterCode adds the error code to the global registry. It returns an error if
+// the code is empty or already registered.
+// Codes are a part of the API contract: clients can branch on them and users can
+// quote them. The registry ensures that two error classes don't use the same code.
+func RegisterCode(code string) error {
+	if code == "" {
+		return NewDomain("error code can't be empty")
+	}
+	codes.Lock()
+	defer codes.Unlock()
+	if codes.m[code] {
+		return NewDomainF("error code %q is already registered", code)
+	}
+	codes.m[code] = true
+	return nil
+}
+
+// RegisteredCodes returns sorted list of all registered error codes.
+func RegisteredCodes() []string {
+	codes.Lock()
+	defer codes.Unlock()
+	var l = make([]string, 0, len(codes.m))
+	for c := range codes.m {
+		l = append(l, c)
+	}
+	sort.Strings(l)
+	return l
+}
+
+// registerSentinel adds the Sentinel to the registry. It returns an error if the
+// Sentinel code is already registered.
+func registerSentinel(s *Sentinel) error {
+	if err := RegisterCode(s.code); err != nil {
+		return err
+	}
+	codes.Lock()
+	codes.sentinels = append(codes.sentinels, s)
+	codes.Unlock()
+	return nil
+}
+
+// Sentinels returns all Sentinels created with Define, sorted by code.
+func Sentinels() []*Sentinel {
+	codes.Lock()
+	defer codes.Unlock()
+	var l = make([]*Sentinel, len(codes.sentinels))
+	copy(l, codes.sentinels)
+	sort.Slice(l, func(i, j int) bool { return l[i].code < l[j].code })
+	return l
+}
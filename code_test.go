@@ -0,0 +1,56 @@
+package errstack
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/robert-zaremba/checkers"
+	. "gopkg.in/check.v1"
+)
+
+type CodeSuite struct{}
+
+func (s *CodeSuite) TestRegisterCode(c *C) {
+	// the registry is global, so the code must be unique across test runs
+	code := fmt.Sprint("test_register_", time.Now().UnixNano())
+	c.Check(RegisterCode(""), NotNil)
+	c.Check(RegisterCode(code), IsNil)
+	c.Check(RegisterCode(code), ErrorMatches, `error code "`+code+`" is already registered`)
+	c.Check(RegisteredCodes(), Contains, code)
+	c.Check(RegisteredCodes(), Contains, errTestNotFound.Code())
+	c.Check(func() { Define(Request, code, "") }, PanicMatches, ".*already registered")
+}
+
+func (s *CodeSuite) TestMarshalCode(c *C) {
+	err := errTestNotFound.New("john")
+	c.Check(err.Code(), Equals, "test_not_found")
+	b, errm := err.MarshalJSON()
+	c.Assert(errm, IsNil)
+	c.Check(string(b), Equals, `{"code":"test_not_found","msg":"user john not found"}`)
+	c.Check(err.WithMsg("more").Code(), Equals, "test_not_found")
+
+	err = NewIO("db failure").WithCode("db")
+	assertMarshal(err, `{"code":"db","incident":"42","msg":"Internal server error: db failure"}`, IO, c)
+
+	err = NewReqDetails("k", "v", "").WithCode("invalid_input")
+	c.Check(err.Code(), Equals, "invalid_input")
+	assertMarshal(err, `{"code":"invalid_input","err":{"k":"v"}}`, Request, c)
+	err = err.WithMsg("msg")
+	assertMarshal(err, `{"code":"invalid_input","err":{"k":"v"},"msg":"msg []"}`, Request, c)
+
+	err = NewReq("no code")
+	c.Check(err.Code(), Equals, "")
+	assertMarshal(err, `{"msg":"no code"}`, Request, c)
+}
+
+func (s *CodeSuite) TestSentinels(c *C) {
+	var found bool
+	for _, s := range Sentinels() {
+		if s == errTestQuota {
+			found = true
+		}
+	}
+	c.Check(found, IsTrue)
+	c.Check(errTestQuota.Status(), Equals, 429)
+	c.Check(errTestNotFound.Format(), Equals, "user %s not found")
+}
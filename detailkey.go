@@ -0,0 +1,53 @@
+package errstack
+
+// DetailKey is a typed key of an error detail. It protects from typos in detail
+// names and removes type assertions from the code reading details.
+// Example:
+//
+//	var UserIDKey = errstack.NewDetailKey[int64]("user_id")
+//
+//	err = UserIDKey.Set(errstack.WrapAsIO(err, "can't load user"), 42)
+//	...
+//	if id, ok := UserIDKey.Get(err); ok { ... }
+type DetailKey[T any] struct {
+	name string
+}
+
+// NewDetailKey creates a new typed detail key.
+func NewDetailKey[T any](name string) DetailKey[T] {
+	return DetailKey[T]{name}
+}
+
+// Name returns the detail name (key in the E.Details map).
+func (k DetailKey[T]) Name() string {
+	return k.name
+}
+
+// Set returns a copy of the error with the detail set (see E.With).
+// If err is nil, nil is returned.
+func (k DetailKey[T]) Set(err E, val T) E {
+	if err == nil {
+		return nil
+	}
+	return err.With(k.name, val)
+}
+
+// Get returns the detail value of the first error in the cause chain which has
+// the detail with the key type.
+func (k DetailKey[T]) Get(err error) (T, bool) {
+	for ; err != nil; err = unwrapOnce(err) {
+		var v interface{}
+		var ok bool
+		switch e := err.(type) {
+		case ownDetailer:
+			v, ok = e.ownDetails().get(k.name)
+		case E:
+			v, ok = e.Details()[k.name]
+		}
+		if t, isT := v.(T); ok && isT {
+			return t, true
+		}
+	}
+	var zero T
+	return zero, false
+}
@@ -0,0 +1,50 @@
+package errstack
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/robert-zaremba/checkers"
+	. "gopkg.in/check.v1"
+)
+
+type DetailKeySuite struct{}
+
+var (
+	testUserIDKey = NewDetailKey[int64]("user_id")
+	testNameKey   = NewDetailKey[string]("name")
+)
+
+func (s *DetailKeySuite) TestSetGet(c *C) {
+	c.Check(testUserIDKey.Set(nil, 1), IsNil)
+	_, ok := testUserIDKey.Get(nil)
+	c.Check(ok, IsFalse)
+
+	err := testUserIDKey.Set(NewIO("db"), 42)
+	id, ok := testUserIDKey.Get(err)
+	c.Check(ok, IsTrue)
+	c.Check(id, Equals, int64(42))
+	c.Check(err.Details(), DeepEquals, map[string]interface{}{"user_id": int64(42)})
+
+	_, ok = testNameKey.Get(err)
+	c.Check(ok, IsFalse)
+
+	// the whole chain is searched, outer errors first
+	werr := fmt.Errorf("handler: %w", testUserIDKey.Set(WrapAsDomain(err, "domain"), 7))
+	id, ok = testUserIDKey.Get(werr)
+	c.Check(ok, IsTrue)
+	c.Check(id, Equals, int64(7))
+	id, _ = testUserIDKey.Get(WrapAsDomain(err, "domain"))
+	c.Check(id, Equals, int64(42))
+
+	// values of other types are skipped
+	err = NewReq("x").With("user_id", "not a number")
+	inner := fmt.Errorf("inner: %w", testUserIDKey.Set(NewIO("inner"), 1))
+	id, ok = testUserIDKey.Get(WrapAsDomain(inner, "outer").With("user_id", "x"))
+	c.Check(ok, IsTrue)
+	c.Check(id, Equals, int64(1))
+	_, ok = testUserIDKey.Get(err)
+	c.Check(ok, IsFalse)
+	_, ok = testUserIDKey.Get(errors.New("x"))
+	c.Check(ok, IsFalse)
+}
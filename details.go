@@ -0,0 +1,84 @@
+package errstack
+
+import "sync"
+
+// details is an immutable list of error details. Newer entries shadow older ones
+// with the same key.
+type details struct {
+	key  string
+	val  interface{}
+	next *details
+}
+
+func (d *details) with(key string, val interface{}) *details {
+	return &details{key, val, d}
+}
+
+// get returns the newest value of the key.
+func (d *details) get(key string) (interface{}, bool) {
+	for ; d != nil; d = d.next {
+		if d.key == key {
+			return d.val, true
+		}
+	}
+	return nil, false
+}
+
+// fill adds details to the map. Existing keys are overwritten.
+func (d *details) fill(m map[string]interface{}) {
+	var ds []*details
+	for ; d != nil; d = d.next {
+		ds = append(ds, d)
+	}
+	for i := len(ds) - 1; i >= 0; i-- {
+		m[ds[i].key] = ds[i].val
+	}
+}
+
+// detailsBox holds details of an error value. It's shared by all copies of the value,
+// so Add is visible through all of them. Derived errors (With, WithMsg...) get a new box.
+type detailsBox struct {
+	mu sync.Mutex
+	d  *details
+}
+
+func newDetailsBox(d *details) *detailsBox {
+	return &detailsBox{d: d}
+}
+
+func (b *detailsBox) snapshot() *details {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.d
+}
+
+func (b *detailsBox) add(key string, val interface{}) {
+	b.mu.Lock()
+	b.d = b.d.with(key, val)
+	b.mu.Unlock()
+}
+
+// ownDetailer is implemented by errors of this package. It returns details of the
+// error layer, without its causes.
+type ownDetailer interface {
+	ownDetails() *details
+}
+
+// chainDetails merges details of all errors in the chain. Details of outer errors
+// overwrite details of their causes.
+func chainDetails(err error) map[string]interface{} {
+	var layers []error
+	for ; err != nil; err = unwrapOnce(err) {
+		layers = append(layers, err)
+	}
+	var m = map[string]interface{}{}
+	for i := len(layers) - 1; i >= 0; i-- {
+		if d, ok := layers[i].(ownDetailer); ok {
+			d.ownDetails().fill(m)
+		}
+	}
+	return m
+}
@@ -10,15 +10,16 @@ func wrapDomain(e error, details string, skip int) E {
 	if e == nil {
 		return nil
 	}
-	if es, ok := e.(errstack); ok && es.kind == Domain {
+	if es, ok := asKind(e, Domain); ok {
 		return es.WithMsg(details)
 	}
 	return newErr(e, details, Domain, skip+1)
 }
 
-// NewDomainF creates new domain error using string formatter
+// NewDomainF creates new domain error using string formatter.
+// It supports the `%w` verb, see Errorf.
 func NewDomainF(format string, a ...interface{}) E {
-	return newDomain(fmt.Sprintf(format, a...), 1)
+	return errorf(Domain, 1, format, a)
 }
 
 // NewDomain creates new domain error from string
@@ -2,6 +2,7 @@ package errstack
 
 import (
 	"encoding/json"
+	"strconv"
 
 	"github.com/facebookgo/stack"
 )
@@ -29,12 +30,27 @@ type E interface {
 	error
 	HasStatusCode
 	HasStacktrace
+	HasIncidentID
 	json.Marshaler
 	IsReq() bool
 	Kind() Kind
 	WithMsg(string) E
+	// Code returns a stable, machine readable error code (if set).
+	Code() string
+	// WithCode returns a copy of the error with the code set.
+	WithCode(code string) E
+	// PublicMsg returns the message which can be displayed to clients (if set).
+	PublicMsg() string
+	// WithPublicMsg returns a copy of the error with the public message set.
+	// See ExposurePolicy.
+	WithPublicMsg(msg string) E
+	// Details returns details of the error merged with details of its causes.
+	// The returned map is a copy.
 	Details() map[string]interface{}
-	Add(key string, payload interface{}) // add more details to the error
+	// With returns a copy of the error with a new detail. The original error is not modified.
+	With(key string, payload interface{}) E
+	// Add adds a detail to the error (and all its copies). Prefer With.
+	Add(key string, payload interface{})
 }
 
 // Kind defines the kind of error that must act differently depending on the error
@@ -59,13 +75,59 @@ const (
 	Domain                    // Internal error causing business domain problem or inconsistency
 )
 
+var kindNames = []string{
+	Other:         "Other",
+	Invalid:       "Invalid",
+	Permission:    "Permission",
+	IO:            "IO",
+	Exist:         "Exist",
+	NotExist:      "NotExist",
+	IsDir:         "IsDir",
+	NotDir:        "NotDir",
+	NotEmpty:      "NotEmpty",
+	Private:       "Private",
+	CannotDecrypt: "CannotDecrypt",
+	Transient:     "Transient",
+	BrokenLink:    "BrokenLink",
+	Request:       "Request",
+	Domain:        "Domain",
+}
+
+// String returns the Kind name.
+func (k Kind) String() string {
+	if int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return "Kind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// IsReq reports whether errors of this kind are request errors.
+func (k Kind) IsReq() bool {
+	return isReq(k)
+}
+
+// ParseKind returns the Kind with the given name (as returned by Kind.String).
+func ParseKind(name string) (Kind, error) {
+	for k, n := range kindNames {
+		if n == name {
+			return Kind(k), nil
+		}
+	}
+	return Other, NewReqF("unknown error kind %q", name)
+}
+
 // IsKind reports whether err is an *Error of the given Kind.
 // If err is nil then Is returns false.
+// When a kind transition was denied (see TransitionPolicy), both the original
+// and the requested kinds match.
 func IsKind(kind Kind, err error) bool {
 	e, ok := err.(E)
 	if !ok {
 		return false
 	}
+	if r, ok := err.(interface{ requestedKind() Kind }); ok && r.requestedKind() == kind && kind != Other {
+		return true
+	}
 	ek := e.Kind()
 	if ek != Other {
 		return ek == kind
@@ -79,10 +141,37 @@ func IsKind(kind Kind, err error) bool {
 	return false
 }
 
+// KindOf returns the kind of the error. Like IsKind, it checks causes of errors
+// of the Other kind. It returns Other if err is not an E.
+func KindOf(err error) Kind {
+	e, ok := err.(E)
+	if !ok {
+		return Other
+	}
+	if k := e.Kind(); k != Other {
+		return k
+	}
+	if ecauser, ok := err.(HasUnderlying); ok && ecauser.Cause() != nil {
+		return KindOf(ecauser.Cause())
+	}
+	return Other
+}
+
 func isReq(kind Kind) bool {
 	return kind == Permission || kind == Exist || kind == NotExist || kind == Private || kind == CannotDecrypt || kind == Request
 }
 
+// unwrapOnce returns the next error in the chain using `Unwrap` or `HasUnderlying`.
+func unwrapOnce(err error) error {
+	if u, ok := err.(interface{ Unwrap() error }); ok {
+		return u.Unwrap()
+	}
+	if c, ok := err.(HasUnderlying); ok {
+		return c.Cause()
+	}
+	return nil
+}
+
 // RootErr returns the underlying cause of the error, if possible.
 // Normally it should be the root error.
 // This method uses `HasUnderlying` interface to extract the cause error.
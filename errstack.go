@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 
 	"github.com/facebookgo/stack"
 )
@@ -13,12 +15,31 @@ type errstack struct {
 	stacktrace stack.Stack
 	msg        string
 	kind       Kind
-	details    map[string]interface{}
+	requested  Kind // kind requested when wrapping, differs from kind if the transition was denied
+	details    *detailsBox
+	def        *Sentinel // definition of the error, if created by a Sentinel
+	code       string
+	publicMsg  string
+	incident   string
+	text       string // message including messages of causes, returned by Error (see Errorf)
 }
 
 func newErr(e error, s string, kind Kind, skip int) *errstack {
+	var actual = kind
+	if e != nil {
+		actual = transitionKind(e, kind, skip+1)
+	}
+	return newErrOfKind(e, s, actual, kind, skip+1)
+}
+
+func newErrOfKind(e error, s string, kind, requested Kind, skip int) *errstack {
 	st := stack.Callers(skip + 1)
-	return &errstack{e, st, s, kind, map[string]interface{}{}}
+	var incident string
+	if !createdAtInit(st) {
+		incident = newIncidentID(e, kind)
+	}
+	return &errstack{err: e, stacktrace: st, msg: s, kind: kind, requested: requested,
+		details: newDetailsBox(nil), incident: incident}
 }
 
 // New creates a new error E
@@ -26,26 +47,182 @@ func New(kind Kind, msg string) E {
 	return newErr(nil, msg, kind, 1)
 }
 
-// Wrap creates new error using error and string message
+// Errorf creates a new error of the kind with a message formatted according to
+// the format specifier. Like in fmt.Errorf, an error operand of the `%w` verb
+// becomes the cause of the error. When the cause is an error of the same kind,
+// the message is added as a new layer (see Wrap). `Error()` returns the formatted
+// text, while JSON and public messages omit messages of the `%w` operands,
+// so causes are not exposed.
+// Example:
+//
+//	errstack.Errorf(errstack.IO, "loading %s: %w", name, err)
+func Errorf(kind Kind, format string, a ...interface{}) E {
+	return errorf(kind, 1, format, a)
+}
+
+func errorf(kind Kind, skip int, format string, a []interface{}) E {
+	var ferr = fmt.Errorf(format, a...)
+	var causes []error
+	switch u := ferr.(type) {
+	case interface{ Unwrap() error }:
+		causes = []error{u.Unwrap()}
+	case interface{ Unwrap() []error }:
+		causes = u.Unwrap()
+	}
+	var cause = Join(causes...)
+	if len(causes) == 1 {
+		cause = causes[0]
+	}
+	if cause == nil {
+		return newErr(nil, ferr.Error(), kind, skip+1)
+	}
+	var msg = causelessMsg(format, a)
+	var e errstack
+	if es, ok := asKind(cause, kind); ok {
+		e = es.WithMsg(msg).(errstack)
+	} else {
+		e = *newErr(cause, msg, kind, skip+1)
+	}
+	e.text = ferr.Error()
+	return e
+}
+
+// hiddenCause replaces `%w` operands in causelessMsg.
+type hiddenCause struct{}
+
+func (hiddenCause) Error() string { return "" }
+
+// causelessMsg formats the message without messages of the `%w` operands.
+func causelessMsg(format string, a []interface{}) string {
+	var args = append([]interface{}(nil), a...)
+	for _, i := range wrapOperands(format) {
+		if i < len(args) {
+			args[i] = hiddenCause{}
+		}
+	}
+	return strings.TrimRight(fmt.Errorf(format, args...).Error(), " :;,-")
+}
+
+// wrapOperands returns indexes of the arguments formatted with the `%w` verb.
+// It follows the fmt rules: flags, width, precision, `*` and explicit argument indexes.
+func wrapOperands(format string) []int {
+	var ops []int
+	var arg int
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+	verb:
+		for i++; i < len(format); i++ {
+			switch c := format[i]; {
+			case strings.IndexByte("+-# .", c) >= 0 || '0' <= c && c <= '9':
+			case c == '[':
+				end := strings.IndexByte(format[i:], ']')
+				if end < 0 {
+					return ops
+				}
+				if n, err := strconv.Atoi(format[i+1 : i+end]); err == nil && n > 0 {
+					arg = n - 1
+				}
+				i += end
+			case c == '*':
+				arg++
+			case c == '%':
+				break verb
+			default:
+				if c == 'w' {
+					ops = append(ops, arg)
+				}
+				arg++
+				break verb
+			}
+		}
+	}
+	return ops
+}
+
+// Wrap creates new error using error and string message.
+// If err is an E of the same kind, the message is added as a new layer of err
+// and its stacktrace is kept.
 func Wrap(err error, kind Kind, msg string) E {
 	if err == nil {
 		return nil
 	}
-	if es, ok := err.(errstack); ok && es.kind == kind {
+	if es, ok := asKind(err, kind); ok {
 		return es.WithMsg(msg)
 	}
 	return newErr(err, msg, kind, 1)
 }
 
+// asKind returns err as errstack if it's an error of the kind. Both values and
+// pointers are matched: constructors return pointers, while WithMsg and With return values.
+func asKind(err error, kind Kind) (errstack, bool) {
+	switch es := err.(type) {
+	case errstack:
+		return es, es.kind == kind
+	case *errstack:
+		if es != nil {
+			return *es, es.kind == kind
+		}
+	}
+	return errstack{}, false
+}
+
 func (e errstack) WithMsg(msg string) E {
+	var incident = e.incident
+	if incident == "" {
+		incident = newIncidentID(nil, e.kind)
+	}
 	return errstack{
-		err:        wrapper{e.msg, e.err},
+		err:        wrapper{e.msg, e.err, e.kind, e.text},
 		msg:        msg,
 		stacktrace: e.stacktrace,
 		kind:       e.kind,
+		requested:  e.requested,
+		def:        e.def,
+		code:       e.code,
+		publicMsg:  e.publicMsg,
+		incident:   incident,
+		details:    newDetailsBox(e.details.snapshot()),
 	}
 }
 
+// copy returns a copy of the error with own details box, so Add doesn't
+// modify the original error.
+func (e errstack) copy() errstack {
+	e.details = newDetailsBox(e.details.snapshot())
+	return e
+}
+
+// Code implements E interface.
+func (e errstack) Code() string {
+	return e.code
+}
+
+// WithCode implements E interface.
+func (e errstack) WithCode(code string) E {
+	e = e.copy()
+	e.code = code
+	return e
+}
+
+// IncidentID implements HasIncidentID interface.
+func (e errstack) IncidentID() string {
+	return e.incident
+}
+
+// PublicMsg implements E interface.
+func (e errstack) PublicMsg() string {
+	return e.publicMsg
+}
+
+// WithPublicMsg implements E interface.
+func (e errstack) WithPublicMsg(msg string) E {
+	e = e.copy()
+	e.publicMsg = msg
+	return e
+}
+
 // IsReq is false for Infrastructure errors.
 // It implements errstack.E interface
 func (e errstack) IsReq() bool {
@@ -58,18 +235,35 @@ func (e errstack) Kind() Kind {
 	return e.kind
 }
 
+func (e errstack) requestedKind() Kind {
+	return e.requested
+}
+
 // Details implements E interface.
 func (e errstack) Details() map[string]interface{} {
-	return e.details
+	return chainDetails(e)
+}
+
+func (e errstack) ownDetails() *details {
+	return e.details.snapshot()
+}
+
+// With implements E interface.
+func (e errstack) With(key string, payload interface{}) E {
+	e.details = newDetailsBox(e.details.snapshot().with(key, payload))
+	return e
 }
 
 // Add implements E interface.
 func (e errstack) Add(key string, payload interface{}) {
-	e.details[key] = payload
+	e.details.add(key, payload)
 }
 
 // StatusCode return HTTP status code
 func (e errstack) StatusCode() int {
+	if e.def != nil && e.def.status != 0 {
+		return e.def.status
+	}
 	if s, ok := e.err.(HasStatusCode); ok {
 		return s.StatusCode()
 	}
@@ -83,34 +277,77 @@ func (e errstack) Cause() error {
 	return e.err
 }
 
+// Unwrap returns the underlying error. It's used by the standard `errors` package.
+func (e errstack) Unwrap() error {
+	return e.err
+}
+
+// Is reports whether the error was created by the `target` Sentinel.
+// It's used by `errors.Is`.
+func (e errstack) Is(target error) bool {
+	return e.def != nil && target == error(e.def)
+}
+
 // Stacktrace returns error creation stacktrace
 func (e errstack) Stacktrace() stack.Stack {
 	return e.stacktrace
 }
 
 func (e errstack) Error() string {
+	if e.text != "" {
+		return e.text
+	}
 	if e.err == nil {
 		return e.msg
 	}
-	return fmt.Sprint(e.msg, " [", e.err.Error(), "]")
+	return msgFormatter.get().Layer(e.msg, e.err.Error())
 }
 
 // MarshalJSON implements Marshaller
 // It will return "Internal server error" without full details when the error
-// is not a request error.
+// is not a request error. The output depends on the ExposurePolicy.
 func (e errstack) MarshalJSON() ([]byte, error) {
-	if e.IsReq() {
+	var exp = exposurePolicy.get()(e)
+	if e.IsReq() || exp == ExposeAll {
 		data := errmap{"msg": e.msg}
-		if e.err != nil {
+		if exp == ExposePublic && e.publicMsg != "" {
+			data["msg"] = e.publicMsg
+		}
+		if w, ok := e.err.(wrapper); ok {
+			data["err"] = w.exposed(exp)
+		} else if e.err != nil {
 			if _, ok := e.err.(json.Marshaler); ok {
 				data["err"] = e.err
-			} else {
+			} else if exp != ExposePublic {
 				data["err"] = e.err.Error()
 			}
 		}
+		e.addJSONIDs(data)
 		return json.Marshal(data)
 	}
-	return json.Marshal("Internal server error: " + e.msg)
+	var msg = internalErrMsg + ": " + e.msg
+	if exp == ExposePublic {
+		msg = internalErrMsg
+		if e.publicMsg != "" {
+			msg = e.publicMsg
+		}
+	}
+	if e.code == "" && e.incident == "" {
+		return json.Marshal(msg)
+	}
+	data := errmap{"msg": msg}
+	e.addJSONIDs(data)
+	return json.Marshal(data)
+}
+
+// addJSONIDs adds code and incident ID to the JSON data
+func (e errstack) addJSONIDs(data errmap) {
+	if e.code != "" {
+		data["code"] = e.code
+	}
+	if e.incident != "" {
+		data["incident"] = e.incident
+	}
 }
 
 // Format implements fmt.Formatter interface
@@ -121,6 +358,9 @@ func (e errstack) Format(s fmt.State, verb rune) {
 			io.WriteString(s, "### ")
 			io.WriteString(s, e.Error())
 			io.WriteString(s, "\n")
+			if e.incident != "" {
+				io.WriteString(s, "incident: "+e.incident+"\n")
+			}
 			io.WriteString(s, e.stacktrace.String())
 			io.WriteString(s, "\n--------------------------------")
 			return
@@ -133,26 +373,50 @@ func (e errstack) Format(s fmt.State, verb rune) {
 	}
 }
 
+// wrapper keeps a message of an error layer replaced by WithMsg.
 type wrapper struct {
-	msg string
-	err error
+	msg  string
+	err  error
+	kind Kind
+	text string
 }
 
 func (e wrapper) Error() string {
+	if e.text != "" {
+		return e.text
+	}
 	if e.err == nil {
 		return e.msg
 	}
-	return fmt.Sprintf("%s [%s]", e.msg, e.err.Error())
+	return msgFormatter.get().Layer(e.msg, e.err.Error())
 }
 
 func (e wrapper) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.exposed(ExposeDefault))
+}
+
+// exposed returns the layer data to render. Causes which are not errors of this
+// package are not exposed with ExposePublic.
+func (e wrapper) exposed(exp Exposure) errmap {
 	data := errmap{"msg": e.msg}
-	if e.err != nil {
-		data["err"] = e.err
+	switch c := e.err.(type) {
+	case nil:
+	case wrapper:
+		data["err"] = c.exposed(exp)
+	case json.Marshaler:
+		data["err"] = c
+	default:
+		if exp != ExposePublic {
+			data["err"] = c.Error()
+		}
 	}
-	return json.Marshal(data)
+	return data
 }
 
 func (e wrapper) Cause() error {
 	return e.err
 }
+
+func (e wrapper) Unwrap() error {
+	return e.err
+}
@@ -65,7 +65,7 @@ func (s *ESuite) TestWrapAsInf(c *C) {
 	err := errors.New("new error")
 
 	werr := WrapAsIOf(err, "one")
-	assertMarshal(werr, `"Internal server error: one"`, IO, c)
+	assertMarshal(werr, `{"incident":"42","msg":"Internal server error: one"}`, IO, c)
 }
 
 func (s *ESuite) TestWrappingNil(c *C) {
@@ -93,8 +93,11 @@ func (s *ESuite) TestWrapping(c *C) {
 		check(tt, WrapAsDomainF(tt, message), Domain)
 		check(tt, WrapAsIO(tt, message), IO)
 		check(tt, WrapAsIOf(tt, message), IO)
-		check(tt, WrapAsReq(tt, message), Request)
-		check(tt, WrapAsReqF(tt, message), Request)
+		// infrastructure errors can't be downgraded to request errors
+		check(tt, WrapAsReq(tt, message), tt.Kind())
+		check(tt, WrapAsReqF(tt, message), tt.Kind())
+		c.Check(IsKind(Request, WrapAsReq(tt, message)), Equals, true)
+		check(tt, Reclassify(tt, Request, message), Request)
 	}
 }
 
@@ -149,6 +152,62 @@ func (s *ESuite) TestIsKind(c *C) {
 	c.Assert(IsKind(Request, err), Equals, true)
 }
 
+func (s *ESuite) TestErrorf(c *C) {
+	base := errors.New("timeout")
+	err := Errorf(IO, "loading %s: %w", "users", base)
+	c.Check(err.Error(), Equals, "loading users: timeout")
+	c.Check(err.Kind(), Equals, IO)
+	c.Check(errors.Unwrap(err), Equals, base)
+	c.Check(errors.Is(err, base), Equals, true)
+	c.Check(err.WithMsg("retry").Error(), Equals, "retry [loading users: timeout]")
+
+	// merging with the cause of the same kind
+	io := NewIO("x").WithMsg("timeout")
+	err = NewIOf("loading %s: %w", "users", io)
+	c.Check(err.Error(), Equals, "loading users: timeout")
+	c.Check(err.Stacktrace(), DeepEquals, io.Stacktrace())
+	c.Check(len(Chain(err)), Equals, 3)
+
+	io2 := NewIO("timeout")
+	err = Errorf(IO, "loading: %w", io2)
+	c.Check(err.Error(), Equals, "loading: timeout")
+	c.Check(err.Stacktrace(), DeepEquals, io2.Stacktrace())
+	c.Check(LayerOf(errors.Unwrap(err)).Msg, Equals, "timeout")
+	c.Check(WrapAsIO(io2, "retry").Stacktrace(), DeepEquals, io2.Stacktrace())
+	c.Check(WrapAsDomain(NewDomain("x"), "y").Error(), Equals, "y [x]")
+	c.Check(WrapAsReq(NewReq("x"), "y").Stacktrace(), HasLen, len(io2.Stacktrace()))
+
+	err = NewReqF("invalid %q: %w", "x", NewReq("bad"))
+	c.Check(err.Kind(), Equals, Request)
+	c.Check(LayerOf(errors.Unwrap(err)).Kind, Equals, Request)
+
+	// transition from Domain to Request is denied
+	err = NewReqF("invalid %q: %w", "x", NewDomain("bad"))
+	c.Check(err.Kind(), Equals, Domain)
+	c.Check(err.StatusCode(), Equals, 500)
+	c.Check(IsKind(Request, err), Equals, true)
+	c.Check(IsKind(Domain, errors.Unwrap(err)), Equals, true)
+
+	err = NewDomainF("a: %w, b: %w", base, io)
+	c.Check(err.Error(), Equals, "a: timeout, b: timeout")
+	c.Check(len(Chain(errors.Unwrap(err))), Equals, 4)
+
+	c.Check(wrapOperands("%w %5.2f %*d %[1]w %% %-+#v %w"), DeepEquals, []int{0, 0, 2})
+	c.Check(wrapOperands("%w"), DeepEquals, []int{0})
+	c.Check(wrapOperands("%"), IsNil)
+
+	err = NewDomainF("no cause %d", 1)
+	c.Check(err.Error(), Equals, "no cause 1")
+	c.Check(errors.Unwrap(err), IsNil)
+}
+
+func (s *ESuite) TestKindOf(c *C) {
+	c.Assert(KindOf(nil), Equals, Other)
+	c.Assert(KindOf(errors.New("new Error")), Equals, Other)
+	c.Assert(KindOf(NewDomain("error")), Equals, Domain)
+	c.Assert(KindOf(Wrap(NewIO("error"), Other, "hi")), Equals, IO)
+}
+
 func (s *ESuite) TestAdd(c *C) {
 	var err = New(Request, "error1")
 	err.Add("key1", "details1")
@@ -158,3 +217,50 @@ func (s *ESuite) TestAdd(c *C) {
 	c.Check(err.Error(), Equals, "error1")
 	c.Check(err.Details(), DeepEquals, map[string]interface{}{"key1": "details2"})
 }
+
+func (s *ESuite) TestWith(c *C) {
+	var err = New(IO, "error1").With("key1", "details1")
+	err2 := err.With("key1", "details2").With("key2", 2)
+	c.Check(err.Details(), DeepEquals, map[string]interface{}{"key1": "details1"})
+	c.Check(err2.Details(), DeepEquals, map[string]interface{}{"key1": "details2", "key2": 2})
+
+	// details are inherited
+	err3 := err2.WithMsg("more")
+	c.Check(err3.Details(), DeepEquals, err2.Details())
+	err3.Add("key3", 3)
+	c.Check(err3.Details()["key3"], Equals, 3)
+	c.Check(err2.Details()["key3"], IsNil)
+
+	// details are merged along the cause chain
+	err4 := WrapAsDomain(err2, "domain").With("key2", "outer")
+	c.Check(err4.Details(), DeepEquals, map[string]interface{}{"key1": "details2", "key2": "outer"})
+
+	// Details returns a copy
+	err4.Details()["key1"] = "changed"
+	c.Check(err4.Details()["key1"], Equals, "details2")
+}
+
+func (s *ESuite) TestRequestDetails(c *C) {
+	err := NewReqDetails("field", "invalid", "")
+	err2 := err.With("user", 1)
+	err2.Add("trace", "x")
+	c.Check(err.Details(), DeepEquals, map[string]interface{}{"field": "invalid"})
+	c.Check(err2.Details(), DeepEquals, map[string]interface{}{"field": "invalid", "user": 1, "trace": "x"})
+	assertMarshal(err2, `{"field":"invalid"}`, Request, c)
+}
+
+func (s *ESuite) TestConcurrentDetails(c *C) {
+	var sentinel = NewIO("shared")
+	var done = make(chan E)
+	for i := 0; i < 10; i++ {
+		go func(i int) {
+			sentinel.Add("k", i)
+			done <- sentinel.With("i", i)
+		}(i)
+	}
+	for i := 0; i < 10; i++ {
+		e := <-done
+		c.Check(e.Details()["i"], NotNil)
+	}
+	c.Check(sentinel.Details()["i"], IsNil)
+}
@@ -0,0 +1,114 @@
+package errstack
+
+// Exposure defines which error information is exposed to clients (eg: in JSON responses).
+type Exposure uint8
+
+// Exposure levels.
+const (
+	// ExposeDefault exposes request errors with messages and causes. For other errors
+	// it exposes only the message prefixed with "Internal server error: ".
+	ExposeDefault Exposure = iota
+	// ExposeAll exposes messages and causes of all errors. Use it only in development.
+	ExposeAll
+	// ExposePublic exposes only the public message (see E.WithPublicMsg) and the code.
+	// Messages of request errors are exposed when there is no public message,
+	// because they are meant to be displayed to users. Causes which are not
+	// errors of this package are not exposed.
+	ExposePublic
+)
+
+// ExposurePolicy decides how the error is exposed to clients. All renderers in this
+// package (MarshalJSON methods, Batch, ValidateRows reports, template helpers) honor
+// the policy set with SetExposurePolicy.
+type ExposurePolicy func(e E) Exposure
+
+// DefaultPolicy exposes all errors using ExposeDefault.
+func DefaultPolicy(E) Exposure {
+	return ExposeDefault
+}
+
+// DevelopmentPolicy exposes everything.
+func DevelopmentPolicy(E) Exposure {
+	return ExposeAll
+}
+
+// ProductionPolicy exposes only public messages and codes.
+func ProductionPolicy(E) Exposure {
+	return ExposePublic
+}
+
+var exposurePolicy = newSetting[ExposurePolicy](DefaultPolicy)
+
+// SetExposurePolicy sets the global exposure policy used by all renderers. nil restores
+// DefaultPolicy. The policy is usually selected depending on the environment:
+//
+//	if env == "production" {
+//		errstack.SetExposurePolicy(errstack.ProductionPolicy)
+//	}
+func SetExposurePolicy(p ExposurePolicy) {
+	if p == nil {
+		p = DefaultPolicy
+	}
+	exposurePolicy.set(p)
+}
+
+const internalErrMsg = "Internal server error"
+
+// exposedMsg returns the error message which can be displayed to clients
+// according to the exposure policy.
+func exposedMsg(e E) string {
+	switch exposurePolicy.get()(e) {
+	case ExposeAll:
+		return e.Error()
+	case ExposePublic:
+		if m := e.PublicMsg(); m != "" {
+			return m
+		}
+		if e.IsReq() {
+			return ownMsg(e)
+		}
+		return internalErrMsg
+	}
+	if e.IsReq() {
+		return e.Error()
+	}
+	return internalMsg(e)
+}
+
+// internalMsg returns the message of an infrastructure error exposed with ExposeDefault.
+// It's the same message as rendered by MarshalJSON.
+func internalMsg(e E) string {
+	switch x := e.(type) {
+	case errstack:
+		return internalErrMsg + ": " + x.msg
+	case *errstack:
+		return internalErrMsg + ": " + x.msg
+	case joinedError:
+		return ownMsg(x)
+	}
+	return internalErrMsg
+}
+
+// ownMsg returns the message of a request error without messages of its causes,
+// which may contain internal details. Members of joined errors are exposed
+// according to the policy.
+func ownMsg(e E) string {
+	switch x := e.(type) {
+	case errstack:
+		return x.msg
+	case *errstack:
+		return x.msg
+	case *request:
+		return x.message(jsonMsgFormatter())
+	case joinedError:
+		var msgs = make([]string, len(x.errors))
+		for i, m := range x.errors {
+			msgs[i] = internalErrMsg
+			if me, ok := m.(E); ok {
+				msgs[i] = exposedMsg(me)
+			}
+		}
+		return msgFormatter.get().Join(msgs)
+	}
+	return internalErrMsg
+}
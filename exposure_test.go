@@ -0,0 +1,119 @@
+package errstack
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	. "gopkg.in/check.v1"
+)
+
+type ExposureSuite struct{}
+
+// sliceErr is an error which is not comparable.
+type sliceErr []string
+
+func (e sliceErr) Error() string { return "secret " + fmt.Sprint([]string(e)) }
+
+var errTestPublic = Define(IO, "test_public", "service %s is not available").Public()
+
+func checkJSON(c *C, v interface{}, expected string) {
+	b, err := json.Marshal(v)
+	c.Assert(err, IsNil)
+	c.Check(string(b), Equals, expected)
+}
+
+func (s *ExposureSuite) TearDownTest(c *C) {
+	SetExposurePolicy(nil)
+}
+
+func (s *ExposureSuite) TestDefaultPolicy(c *C) {
+	checkJSON(c, NewIO("sql: select * from users").WithPublicMsg("try again"),
+		`{"incident":"42","msg":"Internal server error: sql: select * from users"}`)
+	checkJSON(c, WrapAsReq(errors.New("cause"), "msg").WithPublicMsg("public"),
+		`{"err":"cause","msg":"msg"}`)
+	checkJSON(c, NewReqF("msg: %w", errors.New("cause")), `{"err":"cause","msg":"msg"}`)
+}
+
+func (s *ExposureSuite) TestProductionPolicy(c *C) {
+	SetExposurePolicy(ProductionPolicy)
+	checkJSON(c, NewIO("sql: select * from users"), `{"incident":"42","msg":"Internal server error"}`)
+	checkJSON(c, NewIO("sql: select * from users").WithPublicMsg("try again").WithCode("db"),
+		`{"code":"db","incident":"42","msg":"try again"}`)
+	checkJSON(c, errTestPublic.Wrap(errors.New("dial tcp"), "billing"),
+		`{"code":"test_public","incident":"42","msg":"service billing is not available"}`)
+	checkJSON(c, WrapAsReq(errors.New("cause"), "msg"), `{"msg":"msg"}`)
+	checkJSON(c, WrapAsReq(errors.New("cause"), "msg").WithPublicMsg("public"), `{"msg":"public"}`)
+	checkJSON(c, NewReqDetails("k", "v", "msg").WithPublicMsg("public"), `{"err":{"k":"v"},"msg":"public"}`)
+
+	b := NewBuilder()
+	b.Put("k", NewIO("sql: select"))
+	b.Put("k", NewReq("invalid"))
+	c.Check(FieldErrors(b, "k"), DeepEquals, []string{"Internal server error", "invalid"})
+
+	// causes of request errors are not exposed
+	err := WrapAsReq(errors.New("pq: SELECT password FROM users"), "invalid user")
+	checkJSON(c, err, `{"msg":"invalid user"}`)
+	checkJSON(c, WrapAsReq(err, "two"), `{"err":{"msg":"invalid user"},"msg":"two"}`)
+	b.Put("u", err)
+	b.Put("u", WrapAsReq(NewReqDetails("name", "is required", "invalid name"), "invalid user"))
+	b.Put("u", Join(NewReq("a"), WrapAsReq(errors.New("pq: SELECT"), "b")))
+	c.Check(FieldErrors(b, "u"), DeepEquals,
+		[]string{"invalid user", "invalid user [invalid name]", "<JoinedError [a b]>"})
+	err = NewReqF("invalid user %s: %w", "john", errors.New("pq: SELECT password"))
+	c.Check(err.Error(), Equals, "invalid user john: pq: SELECT password")
+	checkJSON(c, err, `{"msg":"invalid user john"}`)
+	c.Check(exposedMsg(err), Equals, "invalid user john")
+
+	b = NewBuilder()
+	b.ForkIdx(1).Put("user", err)
+	report := newRowsReport(1, b.(builder))
+	c.Check(report.Errors[0].Message, Equals, "invalid user john")
+
+	// %w operands which are not comparable
+	err = NewReqF("bad input: %w", sliceErr{"x"})
+	c.Check(err.Error(), Equals, "bad input: secret [x]")
+	checkJSON(c, err, `{"msg":"bad input"}`)
+	err = NewReqF("bad %s: %w", "y", NewReq("x").WithMsg("secret y"))
+	c.Check(exposedMsg(err), Equals, "bad y")
+	err = NewReqF("bad: %w", Join(errors.New("secret join")))
+	c.Check(exposedMsg(err), Equals, "bad")
+	checkJSON(c, err, `{"err":{"errors":["Internal server error"]},"msg":"bad"}`)
+}
+
+func (s *ExposureSuite) TestDevelopmentPolicy(c *C) {
+	SetExposurePolicy(DevelopmentPolicy)
+	checkJSON(c, WrapAsIO(errors.New("dial tcp"), "sql: select").WithPublicMsg("try again"),
+		`{"err":"dial tcp","incident":"42","msg":"sql: select"}`)
+
+	b := NewBuilder()
+	b.Put("k", NewIO("sql: select"))
+	c.Check(FieldErrors(b, "k"), DeepEquals, []string{"sql: select"})
+}
+
+func (s *ExposureSuite) TestCustomPolicy(c *C) {
+	SetExposurePolicy(func(e E) Exposure {
+		if e.Kind() == Domain {
+			return ExposeAll
+		}
+		return ExposePublic
+	})
+	checkJSON(c, NewDomain("invariant"), `{"incident":"42","msg":"invariant"}`)
+	checkJSON(c, NewIO("sql"), `{"incident":"42","msg":"Internal server error"}`)
+}
+
+func (s *ExposureSuite) TestConcurrentPolicy(c *C) {
+	var done = make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			SetExposurePolicy(ProductionPolicy)
+			SetExposurePolicy(nil)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		_, err := json.Marshal(WrapAsIO(errors.New("cause"), "msg"))
+		c.Assert(err, IsNil)
+	}
+	<-done
+}
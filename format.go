@@ -0,0 +1,74 @@
+package errstack
+
+import "strings"
+
+// MessageFormatter formats messages of layered and joined errors returned by
+// `Error()`. Use SetMessageFormatter to change the formatter.
+type MessageFormatter interface {
+	// Layer combines the message of an error layer with the message of its cause.
+	Layer(msg, cause string) string
+	// Join combines messages of joined errors.
+	Join(msgs []string) string
+}
+
+// JSONMessageFormatter is an optional interface of a MessageFormatter. By default
+// messages rendered by MarshalJSON use the bracketed style. If `FormatJSON` returns
+// true, the formatter is used for them as well.
+type JSONMessageFormatter interface {
+	MessageFormatter
+	FormatJSON() bool
+}
+
+type bracketFormatter struct{}
+
+func (bracketFormatter) Layer(msg, cause string) string {
+	return msg + " [" + cause + "]"
+}
+
+func (bracketFormatter) Join(msgs []string) string {
+	return "<JoinedError [" + strings.Join(msgs, " ") + "]>"
+}
+
+type colonFormatter struct{}
+
+func (colonFormatter) Layer(msg, cause string) string {
+	if msg == "" {
+		return cause
+	}
+	if cause == "" {
+		return msg
+	}
+	return msg + ": " + cause
+}
+
+func (colonFormatter) Join(msgs []string) string {
+	return strings.Join(msgs, "; ")
+}
+
+// Built-in message formatters.
+var (
+	// BracketFormatter produces `two [one [new error]]` messages. It's the default.
+	BracketFormatter MessageFormatter = bracketFormatter{}
+	// ColonFormatter produces `two: one: new error` messages, like the standard library.
+	ColonFormatter MessageFormatter = colonFormatter{}
+)
+
+var msgFormatter = newSetting(BracketFormatter)
+
+// SetMessageFormatter sets the formatter of messages returned by `Error()` of all
+// errors. nil restores BracketFormatter. Messages of existing errors are formatted
+// with the new formatter as well, because they are formatted on demand.
+func SetMessageFormatter(f MessageFormatter) {
+	if f == nil {
+		f = BracketFormatter
+	}
+	msgFormatter.set(f)
+}
+
+// jsonMsgFormatter returns the formatter used for messages rendered by MarshalJSON.
+func jsonMsgFormatter() MessageFormatter {
+	if f, ok := msgFormatter.get().(JSONMessageFormatter); ok && f.FormatJSON() {
+		return f
+	}
+	return BracketFormatter
+}
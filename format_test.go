@@ -0,0 +1,69 @@
+package errstack
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	. "gopkg.in/check.v1"
+)
+
+type FormatSuite struct{}
+
+func (s *FormatSuite) TearDownTest(c *C) {
+	SetMessageFormatter(nil)
+}
+
+// upperFormatter is a custom formatter which is also used for JSON messages.
+type upperFormatter struct{}
+
+func (upperFormatter) Layer(msg, cause string) string { return strings.ToUpper(msg) + " / " + cause }
+func (upperFormatter) Join(msgs []string) string      { return strings.Join(msgs, " | ") }
+func (upperFormatter) FormatJSON() bool               { return true }
+
+func (s *FormatSuite) TestBracket(c *C) {
+	err := Join(WrapAsIO(errors.New("one"), "two").WithMsg("three"), NewReq("four"))
+	c.Check(err.Error(), Equals, "<JoinedError [three [two [one]] four]>")
+}
+
+func (s *FormatSuite) TestColon(c *C) {
+	SetMessageFormatter(ColonFormatter)
+	err := WrapAsIO(errors.New("one"), "two").WithMsg("three")
+	c.Check(err.Error(), Equals, "three: two: one")
+	c.Check(Join(err, NewReq("four")).Error(), Equals, "three: two: one; four")
+
+	req := WrapAsReq(NewReqDetails("key", "details", "one"), "two")
+	c.Check(req.Error(), Equals, "two: one key: details\n")
+	c.Check(fmt.Sprintf("%s", req), Equals, "two: one")
+	// JSON messages are not affected
+	data, err2 := req.MarshalJSON()
+	c.Assert(err2, IsNil)
+	c.Check(string(data), Equals, `{"err":{"key":"details"},"msg":"two [one]"}`)
+}
+
+func (s *FormatSuite) TestCustom(c *C) {
+	SetMessageFormatter(upperFormatter{})
+	err := WrapAsIO(errors.New("one"), "two")
+	c.Check(Join(err, errors.New("three")).Error(), Equals, "TWO / one | three")
+
+	req := WrapAsReq(NewReqDetails("key", "details", "one"), "two")
+	data, err2 := req.MarshalJSON()
+	c.Assert(err2, IsNil)
+	c.Check(string(data), Equals, `{"err":{"key":"details"},"msg":"TWO / one"}`)
+}
+
+func (s *FormatSuite) TestConcurrent(c *C) {
+	var done = make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			SetMessageFormatter(ColonFormatter)
+			SetMessageFormatter(upperFormatter{})
+		}
+	}()
+	err := WrapAsIO(errors.New("one"), "two")
+	for i := 0; i < 100; i++ {
+		c.Assert(err.Error(), Not(Equals), "")
+	}
+	<-done
+}
@@ -1,11 +1,16 @@
 module github.com/robert-zaremba/errstack
 
-go 1.13
+go 1.20
 
 require (
 	github.com/davecgh/go-spew v1.1.1
 	github.com/facebookgo/stack v0.0.0-20160209184415-751773369052
-	github.com/kr/text v0.2.0 // indirect
 	github.com/robert-zaremba/checkers v1.0.1
 	gopkg.in/check.v1 v1.0.0-20200227125254-8fa46927fb4f
+	gopkg.in/yaml.v2 v2.4.0
+)
+
+require (
+	github.com/kr/text v0.2.0 // indirect
+	github.com/niemeyer/pretty v0.0.0-20200227124842-a10e7caefd8e // indirect
 )
@@ -0,0 +1,132 @@
+package errstack
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// GroupMode defines how a Group handles errors of its tasks.
+type GroupMode uint8
+
+// Group modes.
+const (
+	// CancelOnError cancels the Group context on the first error. Wait returns the
+	// first error, like UntilFirst.
+	CancelOnError GroupMode = iota
+	// CollectErrors runs all tasks. Wait returns all errors combined with Join.
+	CollectErrors
+	// collectBuilder puts request errors into a Builder, see NewBuilderGroup.
+	collectBuilder
+)
+
+// Group runs tasks concurrently and collects their errors according to the GroupMode.
+// Panics of tasks are recovered and reported as Domain errors with the stacktrace
+// of the panic.
+// Example:
+//
+//	g, ctx := errstack.NewGroup(ctx, errstack.CancelOnError)
+//	for _, url := range urls {
+//		url := url
+//		g.GoCtx(func(ctx context.Context) error { return fetch(ctx, url) })
+//	}
+//	err := g.Wait()
+type Group struct {
+	mode   GroupMode
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	errs   []error
+	b      Builder
+}
+
+// NewGroup creates a Group and a context derived from `ctx`. The context is
+// canceled when Wait returns or, in the CancelOnError mode, when a task fails.
+func NewGroup(ctx context.Context, mode GroupMode) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Group{mode: mode, ctx: ctx, cancel: cancel}, ctx
+}
+
+// NewBuilderGroup creates a Group which puts request errors into the builder under
+// the task keys (see GoKey). Other errors are joined and returned by Wait, so they
+// are not reported as request errors. The builder must not be used until Wait returns.
+func NewBuilderGroup(ctx context.Context, b Builder) (*Group, context.Context) {
+	g, ctx := NewGroup(ctx, collectBuilder)
+	g.b = b
+	return g, ctx
+}
+
+// Go runs `f` in a new goroutine.
+func (g *Group) Go(f func() error) {
+	g.GoKey("", func(context.Context) error { return f() })
+}
+
+// GoCtx runs `f` with the Group context in a new goroutine.
+func (g *Group) GoCtx(f func(ctx context.Context) error) {
+	g.GoKey("", f)
+}
+
+// GoKey runs `f` with the Group context in a new goroutine. Request errors of
+// a Group created with NewBuilderGroup are put under the `key`. In other modes
+// the key is ignored.
+func (g *Group) GoKey(key string, f func(ctx context.Context) error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		g.add(key, g.run(f))
+	}()
+}
+
+func (g *Group) run(f func(context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = panicErr(r)
+		}
+	}()
+	return f(g.ctx)
+}
+
+// panicErr converts a recovered value into a Domain error. It's called by
+// a deferred function, so the stacktrace starts at the panic.
+func panicErr(r interface{}) E {
+	if err, ok := r.(error); ok {
+		return newErr(err, "panic", Domain, 3)
+	}
+	return newErr(nil, fmt.Sprint("panic: ", r), Domain, 3)
+}
+
+func (g *Group) add(key string, err error) {
+	if err == nil {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	switch g.mode {
+	case CancelOnError:
+		if len(g.errs) == 0 {
+			g.errs = append(g.errs, err)
+			g.cancel()
+		}
+	case collectBuilder:
+		if isReqErr(err) {
+			g.b.Put(key, err)
+			return
+		}
+		fallthrough
+	default:
+		g.errs = append(g.errs, err)
+	}
+}
+
+// Wait waits until all tasks finish and returns the collected errors according to
+// the GroupMode. In the CollectErrors mode errors are joined in the order in which
+// tasks finished.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+	if g.mode == CancelOnError && len(g.errs) != 0 {
+		return g.errs[0]
+	}
+	return Join(g.errs...)
+}
@@ -0,0 +1,87 @@
+package errstack
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/robert-zaremba/checkers"
+	. "gopkg.in/check.v1"
+)
+
+type GroupSuite struct{}
+
+func (s *GroupSuite) TestCancelOnError(c *C) {
+	var errDB = errors.New("db is down")
+	g, ctx := NewGroup(context.Background(), CancelOnError)
+	g.Go(func() error { return errDB })
+	g.GoCtx(func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+			return errors.New("not canceled")
+		}
+	})
+	c.Check(g.Wait(), Equals, errDB)
+	c.Check(ctx.Err(), Equals, context.Canceled)
+
+	g, _ = NewGroup(context.Background(), CancelOnError)
+	g.Go(func() error { return nil })
+	c.Check(g.Wait(), IsNil)
+}
+
+func (s *GroupSuite) TestCollectErrors(c *C) {
+	g, ctx := NewGroup(context.Background(), CollectErrors)
+	for i := 0; i < 3; i++ {
+		i := i
+		g.GoCtx(func(ctx context.Context) error {
+			if i == 0 {
+				return nil
+			}
+			return ctx.Err()
+		})
+	}
+	g.Go(func() error { return NewReq("bad") })
+	err := g.Wait()
+	c.Assert(err, FitsTypeOf, joinedError{})
+	c.Check(err.(joinedError).errors, HasLen, 1)
+	c.Check(IsKind(Request, err), IsTrue)
+	c.Check(ctx.Err(), Equals, context.Canceled)
+}
+
+func (s *GroupSuite) TestBuilder(c *C) {
+	var b = NewBuilder()
+	g, _ := NewBuilderGroup(context.Background(), b)
+	g.GoKey("email", func(context.Context) error { return NewReq("email is already used") })
+	g.GoKey("name", func(context.Context) error { return nil })
+	g.GoKey("address", func(context.Context) error { return NewIO("geocoder is down") })
+	err := g.Wait()
+	c.Check(IsKind(IO, err), IsTrue)
+	c.Check(err.(E).IsReq(), IsFalse)
+	c.Check(errMessage(b.Get("email")), Equals, "email is already used")
+	c.Check(b.Get("name"), IsNil)
+	c.Check(b.Get("address"), IsNil)
+}
+
+func groupPanic() error {
+	panic("boom")
+}
+
+func (s *GroupSuite) TestPanic(c *C) {
+	g, _ := NewGroup(context.Background(), CollectErrors)
+	g.Go(groupPanic)
+	g.Go(func() error { panic(errors.New("nil map")) })
+	err := g.Wait()
+	c.Assert(err, NotNil)
+	errs := err.(joinedError).errors
+	c.Assert(errs, HasLen, 2)
+	for _, e := range errs {
+		c.Check(IsKind(Domain, e), IsTrue)
+		if e.Error() == "panic: boom" {
+			c.Check(e.(E).Stacktrace()[0].Name, Equals, "groupPanic")
+		} else {
+			c.Check(e.Error(), Equals, "panic [nil map]")
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package errstack
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+)
+
+// TemplateFuncs returns html/template functions to render form errors. Every function
+// takes errors source as the first argument: a Builder, an E (eg: result of
+// Builder.ToReqErr) or a map of errors, and the error key (as used in Builder.Put) as the
+// second one:
+//
+// + hasError - reports if there are errors under the key, eg: to set a CSS class
+// + fieldErrors - list of error messages under the key
+// + errorList - accessible HTML list of errors under the key
+// + errorID - id of the errorList element, to be used in `aria-describedby`
+//
+// Example:
+//
+//	<input name="email" {{if hasError .Errs "email"}}class="invalid" aria-invalid="true"
+//		aria-describedby="{{errorID "email"}}"{{end}}>
+//	{{errorList .Errs "email"}}
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"hasError":    HasError,
+		"fieldErrors": FieldErrors,
+		"errorList":   ErrorList,
+		"errorID":     ErrorID,
+	}
+}
+
+// HasError checks if there are errors under the key.
+// See TemplateFuncs for supported errors sources.
+func HasError(errs interface{}, key string) bool {
+	return lookupErr(errs, key) != nil
+}
+
+// FieldErrors returns messages of the errors stored under the key.
+// See TemplateFuncs for supported errors sources.
+func FieldErrors(errs interface{}, key string) []string {
+	v := lookupErr(errs, key)
+	if v == nil {
+		return nil
+	}
+	var msgs []string
+	for _, x := range chainValues(v) {
+		msgs = append(msgs, errMessage(x))
+	}
+	return msgs
+}
+
+// ErrorList renders errors stored under the key as an HTML list.
+// It returns an empty string if there are no errors.
+func ErrorList(errs interface{}, key string) template.HTML {
+	msgs := FieldErrors(errs, key)
+	if len(msgs) == 0 {
+		return ""
+	}
+	var buf bytes.Buffer
+	buf.WriteString(`<ul class="errors" id="`)
+	buf.WriteString(ErrorID(key))
+	buf.WriteString(`" role="alert">`)
+	for _, m := range msgs {
+		buf.WriteString("<li>")
+		template.HTMLEscape(&buf, []byte(m))
+		buf.WriteString("</li>")
+	}
+	buf.WriteString("</ul>")
+	return template.HTML(buf.String())
+}
+
+// ErrorID returns the HTML id of the ErrorList element for the key.
+func ErrorID(key string) string {
+	return "errors-" + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' {
+			return r
+		}
+		return '-'
+	}, key)
+}
+
+func lookupErr(errs interface{}, key string) interface{} {
+	switch x := errs.(type) {
+	case Builder:
+		return x.Get(key)
+	case *request:
+		return x.details[key]
+	case E:
+		return x.Details()[key]
+	case errmap:
+		return x[key]
+	case map[string]interface{}:
+		return x[key]
+	}
+	return nil
+}
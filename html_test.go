@@ -0,0 +1,39 @@
+package errstack
+
+import (
+	"bytes"
+	"html/template"
+
+	. "github.com/robert-zaremba/checkers"
+	. "gopkg.in/check.v1"
+)
+
+type HTMLSuite struct{}
+
+const formTmpl = `<input name="email"{{if hasError .Errs "email"}} class="invalid"{{end}}>` +
+	`{{errorList .Errs "email"}}{{range fieldErrors .Errs "addr|city"}}[{{.}}]{{end}}`
+
+func (s *HTMLSuite) TestTemplateFuncs(c *C) {
+	b := NewBuilder()
+	b.Put("email", "is required")
+	b.Put("email", "<invalid>")
+	b.Fork("addr").Put("city", NewDomain("db failure"))
+
+	t := template.Must(template.New("form").Funcs(TemplateFuncs()).Parse(formTmpl))
+	for _, errs := range []interface{}{b, b.ToReqErr()} {
+		var buf bytes.Buffer
+		c.Assert(t.Execute(&buf, map[string]interface{}{"Errs": errs}), IsNil)
+		c.Check(buf.String(), Equals, `<input name="email" class="invalid">`+
+			`<ul class="errors" id="errors-email" role="alert"><li>is required</li><li>&lt;invalid&gt;</li></ul>`+
+			`[Internal server error: db failure]`)
+	}
+}
+
+func (s *HTMLSuite) TestLookup(c *C) {
+	c.Check(HasError(nil, "k"), IsFalse)
+	c.Check(HasError(NewReqDetails("k", "v", ""), "k"), IsTrue)
+	c.Check(HasError(map[string]interface{}{"k": "v"}, "x"), IsFalse)
+	c.Check(FieldErrors(NewBuilder(), "k"), IsNil)
+	c.Check(ErrorList(NewBuilder(), "k"), Equals, template.HTML(""))
+	c.Check(ErrorID("items|0:name"), Equals, "errors-items-0-name")
+}
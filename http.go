@@ -0,0 +1,28 @@
+package errstack
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// WriteResponse writes the error as an HTTP JSON response. The status code is
+// taken from the error (500 if it doesn't provide one), the incident ID (if any)
+// is set in the IncidentHeader header. The body is rendered according to the
+// ExposurePolicy. Errors which are not E are not exposed, even if they implement
+// json.Marshaler. If err is nil, nothing is written.
+func WriteResponse(w http.ResponseWriter, err error) error {
+	if err == nil {
+		return nil
+	}
+	data, errm := json.Marshal(sanitize(err))
+	if errm != nil {
+		return errm
+	}
+	if id := IncidentID(err); id != "" {
+		w.Header().Set(IncidentHeader, id)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(errStatusCode(err))
+	_, errm = w.Write(data)
+	return errm
+}
@@ -0,0 +1,78 @@
+package errstack
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"path/filepath"
+	"strings"
+
+	"github.com/facebookgo/stack"
+)
+
+// HasIncidentID provides a function to return the incident ID: a unique identifier
+// of an error occurrence which correlates the response displayed to the user with logs.
+type HasIncidentID interface {
+	IncidentID() string
+}
+
+// IncidentHeader is the HTTP response header with the incident ID (see WriteResponse).
+const IncidentHeader = "X-Incident-Id"
+
+var incidentIDGenerator = newSetting(randomIncidentID)
+
+// SetIncidentIDGenerator sets the function generating incident IDs of new
+// infrastructure errors, eg: to reuse request IDs of a tracing system. The function
+// is called concurrently. Setting nil disables incident IDs.
+func SetIncidentIDGenerator(f func() string) {
+	incidentIDGenerator.set(f)
+}
+
+func randomIncidentID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// newIncidentID returns the incident ID of the error cause, or a new one if the error
+// kind is not a request kind. Errors created during the package initialization
+// don't have incident IDs, so the ID of their cause is never inherited.
+func newIncidentID(cause error, kind Kind) string {
+	if id := IncidentID(cause); id != "" {
+		return id
+	}
+	var gen = incidentIDGenerator.get()
+	if isReq(kind) || gen == nil {
+		return ""
+	}
+	return gen()
+}
+
+// IncidentID returns the incident ID of the first error in the chain which has it.
+// Incident IDs are assigned to infrastructure and domain errors on creation.
+// Errors created during the package initialization (eg: package level variables)
+// don't get incident IDs, because they are shared by many occurrences. Errors
+// wrapping them, including WithMsg layers, get new IDs.
+// It returns an empty string if there is no incident ID.
+func IncidentID(err error) string {
+	for err != nil {
+		if h, ok := err.(HasIncidentID); ok {
+			if id := h.IncidentID(); id != "" {
+				return id
+			}
+		}
+		err = unwrapOnce(err)
+	}
+	return ""
+}
+
+// createdAtInit checks if the stacktrace was captured during the package initialization.
+func createdAtInit(st stack.Stack) bool {
+	for _, f := range st {
+		if strings.HasPrefix(f.Name, "doInit") && filepath.Base(f.File) == "proc.go" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package errstack
+
+import (
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+
+	. "gopkg.in/check.v1"
+)
+
+type IncidentSuite struct{}
+
+var errTestShared = NewIO("connection refused")
+
+type testLogger struct {
+	ctx []interface{}
+}
+
+func (l *testLogger) Error(msg string, ctx ...interface{}) {
+	l.ctx = ctx
+}
+
+func (s *IncidentSuite) TestIncidentID(c *C) {
+	var n int
+	SetIncidentIDGenerator(func() string {
+		n++
+		return fmt.Sprint("id", n)
+	})
+	defer SetIncidentIDGenerator(func() string { return "42" })
+
+	c.Check(IncidentID(nil), Equals, "")
+	c.Check(IncidentID(errors.New("x")), Equals, "")
+	c.Check(NewReq("x").IncidentID(), Equals, "")
+
+	err := NewIO("db")
+	c.Check(err.IncidentID(), Equals, "id1")
+	c.Check(err.WithMsg("more").IncidentID(), Equals, "id1")
+	// incident ID is inherited from the cause
+	c.Check(WrapAsDomain(err, "domain").IncidentID(), Equals, "id1")
+	c.Check(WrapAsReq(err, "req").IncidentID(), Equals, "id1")
+	c.Check(IncidentID(fmt.Errorf("handler: %w", err)), Equals, "id1")
+	c.Check(NewDomain("x").IncidentID(), Equals, "id2")
+
+	// errors created during the package initialization are shared by occurrences
+	c.Check(errTestShared.IncidentID(), Equals, "")
+	c.Check(WrapAsDomain(errTestShared, "req1").IncidentID(), Equals, "id3")
+	c.Check(WrapAsDomain(errTestShared, "req2").IncidentID(), Equals, "id4")
+	c.Check(WrapAsIO(errTestShared, "req3").IncidentID(), Equals, "id5")
+	c.Check(WrapAsReq(errTestShared, "req4").IncidentID(), Equals, "id6")
+
+	c.Check(strings.Contains(fmt.Sprintf("%+v", err), "\nincident: id1\n"), Equals, true)
+
+	var l testLogger
+	Log(&l, err)
+	c.Check(l.ctx, DeepEquals, []interface{}{err, "incident", "id1"})
+	reqErr := NewReq("x")
+	Log(&l, reqErr)
+	c.Check(l.ctx, DeepEquals, []interface{}{reqErr})
+
+	SetIncidentIDGenerator(nil)
+	c.Check(NewIO("db").IncidentID(), Equals, "")
+	assertMarshal(NewIO("db"), `"Internal server error: db"`, IO, c)
+}
+
+func (s *IncidentSuite) TestWriteResponse(c *C) {
+	w := httptest.NewRecorder()
+	c.Assert(WriteResponse(w, NewIO("db")), IsNil)
+	c.Check(w.Code, Equals, 500)
+	c.Check(w.Header().Get(IncidentHeader), Equals, "42")
+	c.Check(w.Header().Get("Content-Type"), Equals, "application/json")
+	c.Check(w.Body.String(), Equals, `{"incident":"42","msg":"Internal server error: db"}`)
+
+	w = httptest.NewRecorder()
+	c.Assert(WriteResponse(w, NewReqDetails("k", "v", "")), IsNil)
+	c.Check(w.Code, Equals, 400)
+	c.Check(w.Header().Get(IncidentHeader), Equals, "")
+	c.Check(w.Body.String(), Equals, `{"k":"v"}`)
+
+	w = httptest.NewRecorder()
+	c.Assert(WriteResponse(w, errors.New("secret")), IsNil)
+	c.Check(w.Code, Equals, 500)
+	c.Check(w.Body.String(), Equals, `"Internal server error"`)
+
+	w = httptest.NewRecorder()
+	c.Assert(WriteResponse(w, testMarshalerErr{}), IsNil)
+	c.Check(w.Code, Equals, 500)
+	c.Check(w.Body.String(), Equals, `"Internal server error"`)
+
+	w = httptest.NewRecorder()
+	c.Assert(WriteResponse(w, nil), IsNil)
+	c.Check(w.Body.Len(), Equals, 0)
+	c.Check(w.Header(), HasLen, 0)
+}
+
+// testMarshalerErr is a third party error which exposes internal details in JSON.
+type testMarshalerErr struct{}
+
+func (testMarshalerErr) Error() string { return "dsn" }
+
+func (testMarshalerErr) MarshalJSON() ([]byte, error) {
+	return []byte(`"dsn=postgres://u:pw@db"`), nil
+}
@@ -8,7 +8,7 @@ func wrapIO(e error, details string, skip int) E {
 	if e == nil {
 		return nil
 	}
-	if es, ok := e.(errstack); ok && es.kind == IO {
+	if es, ok := asKind(e, IO); ok {
 		return es.WithMsg(details)
 	}
 	return newErr(e, details, IO, skip+1)
@@ -30,9 +30,10 @@ func WrapAsIOf(err error, f string, a ...interface{}) E {
 	return wrapIO(err, fmt.Sprintf(f, a...), 1)
 }
 
-// NewIOf creates new infrastructural error using string formatter
+// NewIOf creates new infrastructural error using string formatter.
+// It supports the `%w` verb, see Errorf.
 func NewIOf(f string, a ...interface{}) E {
-	return newErr(nil, fmt.Sprintf(f, a...), IO, 1)
+	return errorf(IO, 1, f, a)
 }
 
 // NewIO creates new infrastructural error from string
@@ -8,9 +8,27 @@ import (
 
 func init() {
 	//	logger = log15.New()
+	SetIncidentIDGenerator(func() string { return "42" })
 	Suite(&BuilderSuite{})
 	Suite(&ESuite{})
 	Suite(&JoinSuite{})
+	Suite(&BatchSuite{})
+	Suite(&RowsSuite{})
+	Suite(&PutterSuite{})
+	Suite(&CheckSuite{})
+	Suite(&AsyncSuite{})
+	Suite(&SchemaSuite{})
+	Suite(&HTMLSuite{})
+	Suite(&SentinelSuite{})
+	Suite(&CodeSuite{})
+	Suite(&ExposureSuite{})
+	Suite(&IncidentSuite{})
+	Suite(&DetailKeySuite{})
+	Suite(&CauseSuite{})
+	Suite(&MatchSuite{})
+	Suite(&FormatSuite{})
+	Suite(&TransitionSuite{})
+	Suite(&GroupSuite{})
 }
 
 func Test(t *testing.T) { TestingT(t) }
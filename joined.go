@@ -1,17 +1,35 @@
 package errstack
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"io"
 
+	"github.com/facebookgo/stack"
+)
+
+// joinedError is a list of errors. Its kind and status code are derived from its members.
 type joinedError struct {
-	errors []error
+	errors     []error
+	stacktrace stack.Stack
+	code       string
+	publicMsg  string
+	details    *detailsBox
 }
 
 func (je joinedError) Error() string {
-	return fmt.Sprint("<JoinedError ", je.errors, ">")
+	var msgs = make([]string, len(je.errors))
+	for i, e := range je.errors {
+		msgs[i] = e.Error()
+	}
+	return msgFormatter.get().Join(msgs)
 }
 
 // Join creates a new error from list of errors. It filters out nil errors.
 // If there is no not-nil error it returs nil.
+// The returned error implements E. Its kind is the kind of a member with the
+// highest precedence (see SetKindPrecedence) and it's a request error only when all
+// members are request errors.
 func Join(es ...error) error {
 	var filtered = []error{}
 	for _, e := range es {
@@ -22,5 +40,199 @@ func Join(es ...error) error {
 	if len(filtered) == 0 {
 		return nil
 	}
-	return joinedError{filtered}
+	return joinedError{errors: filtered, stacktrace: stack.Callers(1), details: newDetailsBox(nil)}
+}
+
+var defaultKindPrecedence = []Kind{Domain, IO, Transient, BrokenLink, Invalid, IsDir, NotDir,
+	NotEmpty, Other, CannotDecrypt, Private, Permission, NotExist, Exist, Request}
+
+var kindPrecedence = newSetting(defaultKindPrecedence)
+
+// SetKindPrecedence sets the order of kinds used to select the kind of joined errors.
+// Kinds which are not listed have the lowest precedence. Request kinds never win
+// over a member which is not a request error. nil restores the default order:
+// Domain, IO, Transient, BrokenLink, Invalid, IsDir, NotDir, NotEmpty, Other,
+// CannotDecrypt, Private, Permission, NotExist, Exist, Request.
+// The slice is copied, so it can be reused by the caller.
+func SetKindPrecedence(kinds []Kind) {
+	if kinds == nil {
+		kinds = defaultKindPrecedence
+	}
+	kindPrecedence.set(append([]Kind(nil), kinds...))
+}
+
+func kindRank(precedence []Kind, k Kind) int {
+	for i, x := range precedence {
+		if x == k {
+			return i
+		}
+	}
+	return len(precedence)
+}
+
+// Unwrap returns the joined errors. It's used by the standard `errors` package.
+func (je joinedError) Unwrap() []error {
+	return append([]error(nil), je.errors...)
+}
+
+// Kind implements E interface.
+// It returns the kind of a member with the highest precedence. Kinds of request
+// errors are considered only when all members are request errors, so the kind is
+// consistent with IsReq.
+func (je joinedError) Kind() Kind {
+	var req = je.IsReq()
+	var precedence = kindPrecedence.get()
+	var kind Kind
+	var found bool
+	for _, e := range je.errors {
+		k := KindOf(e)
+		if !req && isReq(k) {
+			continue
+		}
+		if !found || kindRank(precedence, k) < kindRank(precedence, kind) {
+			kind, found = k, true
+		}
+	}
+	return kind
+}
+
+// IsReq implements E interface.
+// It's true if all members are request errors.
+func (je joinedError) IsReq() bool {
+	for _, e := range je.errors {
+		if !isReqErr(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// StatusCode returns 400 if all members are request errors and 500 otherwise.
+func (je joinedError) StatusCode() int {
+	if je.IsReq() {
+		return 400
+	}
+	return 500
+}
+
+// Stacktrace returns the stacktrace of Join call.
+func (je joinedError) Stacktrace() stack.Stack {
+	return je.stacktrace
+}
+
+// IncidentID implements HasIncidentID interface.
+// It returns the first incident ID of the members.
+func (je joinedError) IncidentID() string {
+	for _, e := range je.errors {
+		if id := IncidentID(e); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// WithMsg implements E interface.
+func (je joinedError) WithMsg(msg string) E {
+	return newErr(je, msg, je.Kind(), 1)
+}
+
+// copy returns a copy of the error with own details box.
+func (je joinedError) copy() joinedError {
+	je.details = newDetailsBox(je.details.snapshot())
+	return je
+}
+
+// Code implements E interface.
+func (je joinedError) Code() string {
+	return je.code
+}
+
+// WithCode implements E interface.
+func (je joinedError) WithCode(code string) E {
+	je = je.copy()
+	je.code = code
+	return je
+}
+
+// PublicMsg implements E interface.
+func (je joinedError) PublicMsg() string {
+	return je.publicMsg
+}
+
+// WithPublicMsg implements E interface.
+func (je joinedError) WithPublicMsg(msg string) E {
+	je = je.copy()
+	je.publicMsg = msg
+	return je
+}
+
+// Details implements E interface.
+// Details of members are not included.
+func (je joinedError) Details() map[string]interface{} {
+	var m = map[string]interface{}{}
+	je.details.snapshot().fill(m)
+	return m
+}
+
+func (je joinedError) ownDetails() *details {
+	return je.details.snapshot()
+}
+
+// With implements E interface.
+func (je joinedError) With(key string, payload interface{}) E {
+	je.details = newDetailsBox(je.details.snapshot().with(key, payload))
+	return je
+}
+
+// Add implements E interface.
+func (je joinedError) Add(key string, payload interface{}) {
+	je.details.add(key, payload)
+}
+
+// MarshalJSON implements Marshaller interface. Members are rendered in the
+// `errors` list. Members which are not E are sanitized unless the ExposurePolicy
+// exposes everything.
+func (je joinedError) MarshalJSON() ([]byte, error) {
+	var exp = exposurePolicy.get()(je)
+	var errs = make([]interface{}, len(je.errors))
+	for i, e := range je.errors {
+		errs[i] = sanitize(e)
+		if _, ok := e.(E); !ok && exp == ExposeAll {
+			errs[i] = e.Error()
+		}
+	}
+	data := errmap{"errors": errs}
+	if exp == ExposePublic && je.publicMsg != "" {
+		data["msg"] = je.publicMsg
+	}
+	if je.code != "" {
+		data["code"] = je.code
+	}
+	if id := je.IncidentID(); id != "" {
+		data["incident"] = id
+	}
+	return json.Marshal(data)
+}
+
+// Format implements fmt.Formatter interface. `%+v` prints stacktraces of all members.
+func (je joinedError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			io.WriteString(s, "### ")
+			io.WriteString(s, je.Error())
+			io.WriteString(s, "\n")
+			io.WriteString(s, je.stacktrace.String())
+			io.WriteString(s, "\n--------------------------------")
+			for _, e := range je.errors {
+				fmt.Fprintf(s, "\n%+v", e)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, je.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", je.Error())
+	}
 }
@@ -2,6 +2,8 @@ package errstack
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	. "gopkg.in/check.v1"
 )
@@ -28,3 +30,63 @@ func (s *BuilderSuite) TestJoinErrorsNotNil(c *C) {
 	c.Assert(Join(nil, err), Not(IsNil))
 	c.Assert(Join(nil, err, nil), Not(IsNil))
 }
+
+func (s *JoinSuite) TestKind(c *C) {
+	var req = NewReqDetails("email", "is required", "")
+	err := Join(req, New(NotExist, "no user")).(E)
+	c.Check(err.Kind(), Equals, NotExist)
+	c.Check(err.IsReq(), Equals, true)
+	c.Check(err.StatusCode(), Equals, 400)
+
+	err = Join(req, NewIO("db"), NewDomain("x")).(E)
+	c.Check(err.Kind(), Equals, Domain)
+	c.Check(err.IsReq(), Equals, false)
+	c.Check(err.StatusCode(), Equals, 500)
+	c.Check(IsKind(Domain, err), Equals, true)
+
+	err = Join(req, errors.New("x")).(E)
+	c.Check(err.Kind(), Equals, Other)
+	c.Check(err.StatusCode(), Equals, 500)
+
+	SetKindPrecedence([]Kind{Request, IO})
+	defer SetKindPrecedence(nil)
+	err = Join(NewDomain("x"), NewIO("db"), req).(E)
+	c.Check(err.Kind(), Equals, IO)
+	c.Check(err.IsReq(), Equals, false)
+	c.Check(err.StatusCode(), Equals, 500)
+
+	err = Join(New(NotExist, "x"), req).(E)
+	c.Check(err.Kind(), Equals, Request)
+	c.Check(err.IsReq(), Equals, true)
+	c.Check(err.StatusCode(), Equals, 400)
+}
+
+func (s *JoinSuite) TestUnwrap(c *C) {
+	var inner = errors.New("inner")
+	err := Join(NewReq("a"), WrapAsIO(inner, "io"))
+	c.Check(errors.Is(err, inner), Equals, true)
+	c.Check(err.(interface{ Unwrap() []error }).Unwrap(), HasLen, 2)
+	c.Check(IncidentID(err), Equals, "42")
+	c.Check(err.(E).WithMsg("batch").Error(), Equals, "batch [<JoinedError [a io [inner]]>]")
+}
+
+func (s *JoinSuite) TestMarshalJSON(c *C) {
+	err := Join(NewReqDetails("email", "is required", ""), errors.New("dial tcp")).(E).WithCode("multi")
+	data, err2 := err.MarshalJSON()
+	c.Assert(err2, IsNil)
+	c.Check(string(data), Equals,
+		`{"code":"multi","errors":[{"email":"is required"},"Internal server error"]}`)
+
+	err = Join(NewIO("db")).(E).With("k", "v")
+	c.Check(err.Details(), DeepEquals, map[string]interface{}{"k": "v"})
+	data, err2 = err.MarshalJSON()
+	c.Assert(err2, IsNil)
+	c.Check(string(data), Equals, `{"errors":[{"incident":"42","msg":"Internal server error: db"}],"incident":"42"}`)
+}
+
+func (s *JoinSuite) TestFormat(c *C) {
+	out := fmt.Sprintf("%+v", Join(NewReq("a"), NewIO("b")))
+	c.Check(strings.Count(out, "### "), Equals, 3)
+	c.Check(strings.Count(out, "TestFormat"), Equals, 3)
+	c.Check(fmt.Sprintf("%s", Join(NewReq("a"))), Equals, "<JoinedError [a]>")
+}
@@ -0,0 +1,50 @@
+package errstack
+
+import (
+	"github.com/facebookgo/stack"
+)
+
+// Layer describes a single node of an error tree, without its causes.
+type Layer struct {
+	Kind    Kind                   // Other if the error doesn't have a kind
+	Msg     string                 // message of the layer
+	Details map[string]interface{} // details added to the layer
+	Stack   stack.Stack            // creation stacktrace, nil if not available
+}
+
+// LayerOf returns the information about the err node. It's meant to be used with
+// Walk or Chain. Messages of errors which don't belong to this package are
+// returned as is, so they may contain messages of their causes.
+func LayerOf(err error) Layer {
+	var l = Layer{Details: map[string]interface{}{}}
+	switch e := err.(type) {
+	case nil:
+		return l
+	case errstack:
+		l.Msg = e.msg
+	case *errstack:
+		l.Msg = e.msg
+	case *request:
+		l.Msg = e.message(msgFormatter.get())
+		for k, v := range e.details {
+			l.Details[k] = v
+		}
+	case wrapper:
+		l.Msg, l.Kind = e.msg, e.kind
+	case joinedError:
+	default:
+		l.Msg = err.Error()
+	}
+	if e, ok := err.(E); ok {
+		l.Kind = e.Kind()
+		if d, ok := err.(ownDetailer); ok {
+			d.ownDetails().fill(l.Details)
+		} else {
+			l.Details = e.Details()
+		}
+	}
+	if s, ok := err.(HasStacktrace); ok {
+		l.Stack = s.Stacktrace()
+	}
+	return l
+}
@@ -0,0 +1,56 @@
+package errstack
+
+import "errors"
+
+// Matcher runs the first matching branch for an error. Branches are evaluated
+// in order and at most one of them is run. Nothing is run for a nil error.
+// Example:
+//
+//	errstack.Match(err).
+//		On(errstack.NotExist, func(err error) { w.WriteHeader(404) }).
+//		OnIs(sql.ErrNoRows, func(err error) { w.WriteHeader(404) }).
+//		OnAs(&pqErr, func(err error) { handlePq(pqErr) }).
+//		Default(func(err error) { errstack.WriteResponse(w, err) })
+type Matcher struct {
+	err     error
+	matched bool
+}
+
+// Match creates a Matcher for the error.
+func Match(err error) *Matcher {
+	return &Matcher{err: err, matched: err == nil}
+}
+
+// On runs `fn` if the error is of the given kind (see IsKind).
+func (m *Matcher) On(kind Kind, fn func(error)) *Matcher {
+	return m.when(IsKind(kind, m.err), fn)
+}
+
+// OnIs runs `fn` if `errors.Is(err, target)` is true.
+func (m *Matcher) OnIs(target error, fn func(error)) *Matcher {
+	return m.when(m.err != nil && errors.Is(m.err, target), fn)
+}
+
+// OnAs runs `fn` if `errors.As(err, target)` is true. `target` is set before
+// `fn` is called.
+func (m *Matcher) OnAs(target interface{}, fn func(error)) *Matcher {
+	return m.when(m.err != nil && !m.matched && errors.As(m.err, target), fn)
+}
+
+// Default runs `fn` if no other branch matched.
+func (m *Matcher) Default(fn func(error)) {
+	m.when(true, fn)
+}
+
+// Matched reports whether any branch matched (or the error is nil).
+func (m *Matcher) Matched() bool {
+	return m.matched
+}
+
+func (m *Matcher) when(cond bool, fn func(error)) *Matcher {
+	if !m.matched && cond {
+		m.matched = true
+		fn(m.err)
+	}
+	return m
+}
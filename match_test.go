@@ -0,0 +1,46 @@
+package errstack
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	. "github.com/robert-zaremba/checkers"
+	. "gopkg.in/check.v1"
+)
+
+type MatchSuite struct{}
+
+func (s *MatchSuite) match(err error) string {
+	var pathErr *os.PathError
+	var res = "none"
+	Match(err).
+		On(NotExist, func(error) { res = "not exist" }).
+		On(Permission, func(error) { res = "permission" }).
+		OnIs(io.EOF, func(error) { res = "eof" }).
+		OnAs(&pathErr, func(error) { res = "path " + pathErr.Path }).
+		Default(func(e error) { res = "default " + e.Error() })
+	return res
+}
+
+func (s *MatchSuite) TestMatch(c *C) {
+	c.Check(s.match(nil), Equals, "none")
+	c.Check(s.match(NewReqF("x")), Equals, "default x")
+	c.Check(s.match(New(NotExist, "x")), Equals, "not exist")
+	c.Check(s.match(Wrap(New(Permission, "x"), Other, "y")), Equals, "permission")
+	c.Check(s.match(WrapAsIO(io.EOF, "read")), Equals, "eof")
+	c.Check(s.match(WrapAsIO(&os.PathError{Op: "open", Path: "/tmp/x", Err: io.ErrClosedPipe}, "open")),
+		Equals, "path /tmp/x")
+	c.Check(s.match(WrapAsDomain(io.EOF, "wrapped")), Equals, "eof")
+}
+
+func (s *MatchSuite) TestMatched(c *C) {
+	c.Check(Match(nil).Matched(), IsTrue)
+	c.Check(Match(errors.New("x")).On(IO, func(error) {}).Matched(), IsFalse)
+
+	var calls int
+	m := Match(New(IO, "x")).On(IO, func(error) { calls++ }).On(IO, func(error) { calls++ })
+	m.Default(func(error) { calls++ })
+	c.Check(m.Matched(), IsTrue)
+	c.Check(calls, Equals, 1)
+}
@@ -1,5 +1,10 @@
 package errstack
 
+import (
+	"strconv"
+	"strings"
+)
+
 // StubPutter ignores all input. It only contains information whether any input was submitted
 type StubPutter struct {
 	hasError bool
@@ -24,3 +29,109 @@ func (rp *StubPutter) ForkIdx(_ int) Putter {
 func (rp *StubPutter) Put(_ interface{}) {
 	rp.hasError = true
 }
+
+// PutRecord is a value submitted to the RecordingPutter.
+type PutRecord struct {
+	Path  []string
+	Value interface{}
+}
+
+// Key returns the record path joined in the same way as Builder Putter joins forked keys.
+func (r PutRecord) Key() string {
+	return strings.Join(r.Path, ":")
+}
+
+// RecordingPutter records all submitted values together with the forked path.
+// All forks share the same records.
+type RecordingPutter struct {
+	path    []string
+	records *[]PutRecord
+}
+
+// NewRecordingPutter creates new RecordingPutter with an empty path.
+func NewRecordingPutter() *RecordingPutter {
+	return &RecordingPutter{records: &[]PutRecord{}}
+}
+
+// Records returns all recorded values in the submission order.
+func (rp *RecordingPutter) Records() []PutRecord {
+	return *rp.records
+}
+
+// HasError checks if any error occurred
+func (rp *RecordingPutter) HasError() bool {
+	return len(*rp.records) > 0
+}
+
+// Fork creates a putter which records values under the `prefix` sub-path
+func (rp *RecordingPutter) Fork(prefix string) Putter {
+	return &RecordingPutter{appendPath(rp.path, prefix), rp.records}
+}
+
+// ForkIdx calls Fork with an `int`
+func (rp *RecordingPutter) ForkIdx(idx int) Putter {
+	return rp.Fork(strconv.Itoa(idx))
+}
+
+// Put records new error
+func (rp *RecordingPutter) Put(value interface{}) {
+	*rp.records = append(*rp.records, PutRecord{rp.path, value})
+}
+
+// TeePutter submits every value to all underlying putters.
+type TeePutter []Putter
+
+// Fork forks all underlying putters
+func (tp TeePutter) Fork(prefix string) Putter {
+	var forks = make(TeePutter, len(tp))
+	for i, p := range tp {
+		forks[i] = p.Fork(prefix)
+	}
+	return forks
+}
+
+// ForkIdx calls Fork with an `int`
+func (tp TeePutter) ForkIdx(idx int) Putter {
+	return tp.Fork(strconv.Itoa(idx))
+}
+
+// Put submits the value to all underlying putters
+func (tp TeePutter) Put(value interface{}) {
+	for _, p := range tp {
+		p.Put(value)
+	}
+}
+
+// FuncPutter is an adapter to use an ordinary function as a Putter. The function
+// is called with the forked path and the submitted value.
+// Example - count validation failures by field:
+//
+//	var counter = FuncPutter(func(path []string, _ interface{}) {
+//		failures.WithLabelValues(strings.Join(path, ".")).Inc()
+//	})
+//	validateName(obj.Name, TeePutter{errb.Putter("name"), counter.Fork("name")})
+type FuncPutter func(path []string, value interface{})
+
+// Fork creates a putter which calls the function with the `prefix` sub-path
+func (f FuncPutter) Fork(prefix string) Putter {
+	return FuncPutter(func(path []string, value interface{}) {
+		f(append([]string{prefix}, path...), value)
+	})
+}
+
+// ForkIdx calls Fork with an `int`
+func (f FuncPutter) ForkIdx(idx int) Putter {
+	return f.Fork(strconv.Itoa(idx))
+}
+
+// Put calls the function with an empty path
+func (f FuncPutter) Put(value interface{}) {
+	f(nil, value)
+}
+
+// appendPath returns a new path, so forks never share the underlying array
+func appendPath(path []string, elem string) []string {
+	var p = make([]string, len(path), len(path)+1)
+	copy(p, path)
+	return append(p, elem)
+}
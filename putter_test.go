@@ -0,0 +1,47 @@
+package errstack
+
+import (
+	"strings"
+
+	. "github.com/robert-zaremba/checkers"
+	. "gopkg.in/check.v1"
+)
+
+type PutterSuite struct{}
+
+func validateNameP(name string, p Putter) {
+	if len(name) < 3 {
+		p.Put("name is too short")
+	}
+}
+
+func (s *PutterSuite) TestRecordingPutter(c *C) {
+	rp := NewRecordingPutter()
+	c.Check(rp.HasError(), IsFalse)
+	validateNameP("al", rp.Fork("users").ForkIdx(1).Fork("name"))
+	validateNameP("john", rp.Fork("users").ForkIdx(2).Fork("name"))
+	rp.Put("root")
+
+	c.Check(rp.HasError(), IsTrue)
+	c.Assert(rp.Records(), DeepEquals, []PutRecord{
+		{[]string{"users", "1", "name"}, "name is too short"},
+		{nil, "root"},
+	})
+	c.Check(rp.Records()[0].Key(), Equals, "users:1:name")
+}
+
+func (s *PutterSuite) TestTeeAndFuncPutter(c *C) {
+	var counts = map[string]int{}
+	var counter = FuncPutter(func(path []string, _ interface{}) {
+		counts[strings.Join(path, ".")]++
+	})
+	b := NewBuilder()
+	tp := TeePutter{b.Putter("users"), counter.Fork("users")}
+	validateNameP("al", tp.ForkIdx(1))
+	validateNameP("bo", tp.ForkIdx(1))
+	validateNameP("xi", tp.ForkIdx(2))
+
+	c.Check(counts, DeepEquals, map[string]int{"users.1": 2, "users.2": 1})
+	c.Check(b.Get("users:1"), DeepEquals, chain{"name is too short", "name is too short"})
+	c.Check(b.Get("users:2"), Equals, "name is too short")
+}
@@ -12,7 +12,11 @@ import (
 type request struct {
 	details    errmap
 	msg        string
+	layers     []string // messages added with WithMsg, the outermost is the last one
 	stacktrace stack.Stack
+	code       string
+	publicMsg  string
+	extra      *detailsBox // details added with Add / With
 }
 
 func init() {
@@ -33,13 +37,63 @@ func (r *request) Kind() Kind {
 }
 
 // Details implements E interface.
+// It returns a copy of field errors merged with details added by Add or With.
 func (r *request) Details() map[string]interface{} {
-	return r.details
+	var m = make(map[string]interface{}, len(r.details))
+	for k, v := range r.details {
+		m[k] = v
+	}
+	r.extra.snapshot().fill(m)
+	return m
+}
+
+func (r *request) ownDetails() *details {
+	return r.extra.snapshot()
+}
+
+// With implements E interface.
+func (r *request) With(key string, payload interface{}) E {
+	r2 := *r // make a copy
+	r2.extra = newDetailsBox(r.extra.snapshot().with(key, payload))
+	return &r2
 }
 
 // Add implements E interface.
+// Details are not added to field errors, so they are not rendered in responses.
 func (r *request) Add(key string, payload interface{}) {
-	r.details[key] = payload
+	r.extra.add(key, payload)
+}
+
+// Code implements E interface.
+func (r *request) Code() string {
+	return r.code
+}
+
+// WithCode implements E interface.
+func (r *request) WithCode(code string) E {
+	r2 := *r // make a copy
+	r2.extra = newDetailsBox(r.extra.snapshot())
+	r2.code = code
+	return &r2
+}
+
+// IncidentID implements HasIncidentID interface.
+// Request errors don't have incident IDs.
+func (r *request) IncidentID() string {
+	return ""
+}
+
+// PublicMsg implements E interface.
+func (r *request) PublicMsg() string {
+	return r.publicMsg
+}
+
+// WithPublicMsg implements E interface.
+func (r *request) WithPublicMsg(msg string) E {
+	r2 := *r // make a copy
+	r2.extra = newDetailsBox(r.extra.snapshot())
+	r2.publicMsg = msg
+	return &r2
 }
 
 // StatusCode return HTTP status code
@@ -53,15 +107,28 @@ func (r *request) Stacktrace() stack.Stack {
 
 // Error implements error interface
 func (r *request) Error() string {
-	return fmt.Sprintf("%s %v", r.msg, r.details)
+	return fmt.Sprintf("%s %v", r.message(msgFormatter.get()), r.details)
 }
 
 // MarshalJSON implements Marshaller interface
+// The public message is used instead of the message when the ExposurePolicy
+// requires it.
 func (r *request) MarshalJSON() ([]byte, error) {
-	if r.msg == "" {
+	var msg = r.message(jsonMsgFormatter())
+	if r.publicMsg != "" && exposurePolicy.get()(r) == ExposePublic {
+		msg = r.publicMsg
+	}
+	if msg == "" && r.code == "" {
 		return json.Marshal(r.details)
 	}
-	return json.Marshal(errmap{"msg": r.msg, "err": r.details})
+	data := errmap{"err": r.details}
+	if msg != "" {
+		data["msg"] = msg
+	}
+	if r.code != "" {
+		data["code"] = r.code
+	}
+	return json.Marshal(data)
 }
 
 // Format implements fmt.Formatter interface
@@ -70,7 +137,7 @@ func (r *request) Format(s fmt.State, verb rune) {
 	case 'v':
 		if s.Flag('+') {
 			io.WriteString(s, "### ")
-			io.WriteString(s, r.msg)
+			io.WriteString(s, r.message(msgFormatter.get()))
 			fmt.Fprintf(s, " %v\n", r.details)
 			io.WriteString(s, r.stacktrace.String())
 			io.WriteString(s, "\n--------------------------------")
@@ -78,21 +145,31 @@ func (r *request) Format(s fmt.State, verb rune) {
 		}
 		fallthrough
 	case 's':
-		io.WriteString(s, r.msg)
+		io.WriteString(s, r.message(msgFormatter.get()))
 	case 'q':
-		fmt.Fprintf(s, "%q", r.msg)
+		fmt.Fprintf(s, "%q", r.message(msgFormatter.get()))
 	}
 }
 
 func (r *request) WithMsg(msg string) E {
 	r2 := *r // make a copy
-	r2.msg = fmt.Sprintf("%s [%s]", msg, r.msg)
+	r2.layers = append(r.layers[:len(r.layers):len(r.layers)], msg)
+	r2.extra = newDetailsBox(r.extra.snapshot())
 	return &r2
 }
 
+// message returns the message including layers added with WithMsg.
+func (r *request) message(f MessageFormatter) string {
+	var msg = r.msg
+	for _, l := range r.layers {
+		msg = f.Layer(l, msg)
+	}
+	return msg
+}
+
 func newRequest(m map[string]interface{}, msg string, skip int) E {
 	st := stack.Callers(skip + 1)
-	return &request{m, msg, st}
+	return &request{details: m, msg: msg, stacktrace: st, extra: newDetailsBox(nil)}
 }
 
 // NewReqDetails creates a request error.
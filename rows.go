@@ -0,0 +1,197 @@
+package errstack
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RowReader is an iterator over table rows. It returns io.EOF when there are no
+// more rows. *csv.Reader implements this interface.
+type RowReader interface {
+	Read() ([]string, error)
+}
+
+// Row is a table row passed to row validators.
+type Row struct {
+	// Index is the 1-based number of the record in the input (including header),
+	// so it matches the row number displayed by spreadsheets.
+	Index  int
+	Header []string
+	Values []string
+	b      Builder
+}
+
+// Get returns value of the column. It returns an empty string if the column
+// doesn't exist.
+func (r Row) Get(column string) string {
+	for i, h := range r.Header {
+		if h == column && i < len(r.Values) {
+			return r.Values[i]
+		}
+	}
+	return ""
+}
+
+// Putter returns a Putter for errors of the row column.
+func (r Row) Putter(column string) Putter {
+	return r.b.Putter(column)
+}
+
+// Put adds an error which is not related to any particular column.
+func (r Row) Put(value interface{}) {
+	r.b.Put("", value)
+}
+
+// RowValidator validates a single row and puts the errors using the row Putters.
+type RowValidator func(Row)
+
+// RowsConfig configures ValidateRows.
+type RowsConfig struct {
+	// Header informs that the first row is a header with column names.
+	Header bool
+	// Builder options used to cap the number of collected errors.
+	Builder []BuilderOption
+}
+
+// ValidateRows runs validators over all rows of the reader and collects the errors
+// into a report. Validation stops early when the error limit is reached.
+// Malformed CSV records are reported as row errors. If the header is malformed,
+// its error is reported and no rows are validated, because columns are unknown.
+// Error is returned only when the reader fails.
+// Example:
+//
+//	report, err := ValidateRows(csv.NewReader(f), RowsConfig{Header: true}, func(r Row) {
+//		if r.Get("email") == "" {
+//			r.Putter("email").Put("email is required")
+//		}
+//	})
+func ValidateRows(r RowReader, cfg RowsConfig, validators ...RowValidator) (*RowsReport, error) {
+	var b = NewBuilder(cfg.Builder...).(builder)
+	var header []string
+	var idx, rows int
+	for !b.Full() {
+		values, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		idx++
+		if err != nil {
+			if perr, ok := err.(*csv.ParseError); ok {
+				b.ForkIdx(idx).Put("", perr.Err.Error())
+				if cfg.Header && header == nil {
+					break
+				}
+				continue
+			}
+			return nil, WrapAsIOf(err, "can't read row %d", idx)
+		}
+		if cfg.Header && header == nil {
+			header = values
+			continue
+		}
+		rows++
+		row := Row{idx, header, values, b.ForkIdx(idx)}
+		for _, v := range validators {
+			v(row)
+		}
+	}
+	return newRowsReport(rows, b), nil
+}
+
+// RowError is a single error of the RowsReport.
+type RowError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// RowsReport is a result of ValidateRows. It can be downloaded as CSV or JSON.
+type RowsReport struct {
+	// Rows is the number of validated data rows. The header and malformed
+	// records are not counted.
+	Rows      int        `json:"rows"`
+	Truncated int        `json:"truncated,omitempty"`
+	Errors    []RowError `json:"errors"`
+}
+
+func newRowsReport(rows int, b builder) *RowsReport {
+	var r = &RowsReport{Rows: rows, Truncated: len(b.s.dropped), Errors: []RowError{}}
+	for k, v := range b.m {
+		var column string
+		i := strings.Index(k, builderSep)
+		if i >= 0 {
+			k, column = k[:i], k[i+1:]
+		}
+		row, _ := strconv.Atoi(k)
+		for _, x := range chainValues(v) {
+			r.Errors = append(r.Errors, RowError{row, column, errCode(x), errMessage(x)})
+		}
+	}
+	sort.SliceStable(r.Errors, func(i, j int) bool {
+		ei, ej := r.Errors[i], r.Errors[j]
+		if ei.Row != ej.Row {
+			return ei.Row < ej.Row
+		}
+		return ei.Column < ej.Column
+	})
+	return r
+}
+
+// NotNil checks if there are any errors in the report.
+func (r *RowsReport) NotNil() bool {
+	return len(r.Errors) > 0
+}
+
+var rowsCSVHeader = []string{"row", "column", "code", "message"}
+
+// WriteCSV writes the report errors as CSV with row, column, code and message columns.
+func (r *RowsReport) WriteCSV(w io.Writer) error {
+	var cw = csv.NewWriter(w)
+	if err := cw.Write(rowsCSVHeader); err != nil {
+		return err
+	}
+	for _, e := range r.Errors {
+		if err := cw.Write([]string{strconv.Itoa(e.Row), e.Column, e.Code, e.Message}); err != nil {
+			return err
+		}
+	}
+	if r.Truncated > 0 {
+		msg := fmt.Sprintf("%d more errors were dropped", r.Truncated)
+		if err := cw.Write([]string{"", "", TruncatedKey, msg}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the report as JSON.
+func (r *RowsReport) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(r)
+}
+
+// errCode returns the code of a Builder value if it provides one.
+func errCode(v interface{}) string {
+	if c, ok := v.(interface{ Code() string }); ok {
+		return c.Code()
+	}
+	return ""
+}
+
+// errMessage returns a text representation of a Builder value.
+// Messages of E values are exposed according to the ExposurePolicy.
+func errMessage(v interface{}) string {
+	if e, ok := v.(E); ok {
+		return exposedMsg(e)
+	}
+	if err, ok := v.(error); ok {
+		return err.Error()
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,91 @@
+package errstack
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+
+	. "github.com/robert-zaremba/checkers"
+	. "gopkg.in/check.v1"
+)
+
+type RowsSuite struct{}
+
+const rowsInput = `name,email
+john,john@example.com
+,bob@example.com
+al,
+`
+
+func validateUser(r Row) {
+	if r.Get("name") == "" {
+		r.Putter("name").Put(NewReq("name is required"))
+	} else if len(r.Get("name")) < 3 {
+		r.Putter("name").Put("name is too short")
+	}
+	if r.Get("email") == "" {
+		r.Putter("email").Put("email is required")
+	}
+}
+
+func (s *RowsSuite) TestValidateRows(c *C) {
+	report, err := ValidateRows(csv.NewReader(strings.NewReader(rowsInput)),
+		RowsConfig{Header: true}, validateUser)
+	c.Assert(err, IsNil)
+	c.Assert(report.NotNil(), IsTrue)
+	c.Check(report.Rows, Equals, 3)
+	c.Check(report.Errors, DeepEquals, []RowError{
+		{3, "name", "", "name is required"},
+		{4, "email", "", "email is required"},
+		{4, "name", "", "name is too short"},
+	})
+
+	var buf bytes.Buffer
+	c.Assert(report.WriteCSV(&buf), IsNil)
+	c.Check(buf.String(), Equals, `row,column,code,message
+3,name,,name is required
+4,email,,email is required
+4,name,,name is too short
+`)
+}
+
+func (s *RowsSuite) TestValidateRowsMalformed(c *C) {
+	var validated int
+	var count = func(Row) { validated++ }
+	report, err := ValidateRows(csv.NewReader(strings.NewReader("name,email\n\"a\"b,x\nc,d\n")),
+		RowsConfig{Header: true}, count)
+	c.Assert(err, IsNil)
+	c.Check(report.Rows, Equals, 1)
+	c.Check(report.Errors, HasLen, 1)
+	c.Check(report.Errors[0].Row, Equals, 2)
+
+	// rows can't be validated without the header
+	validated = 0
+	report, err = ValidateRows(csv.NewReader(strings.NewReader("\"name\"x,email\na,b\n")),
+		RowsConfig{Header: true}, count)
+	c.Assert(err, IsNil)
+	c.Check(validated, Equals, 0)
+	c.Check(report.Rows, Equals, 0)
+	c.Check(report.Errors, HasLen, 1)
+	c.Check(report.Errors[0].Row, Equals, 1)
+}
+
+func (s *RowsSuite) TestValidateRowsLimit(c *C) {
+	input := "a\n\n1\n2\n3\n4\n"
+	report, err := ValidateRows(csv.NewReader(strings.NewReader(input)),
+		RowsConfig{Builder: []BuilderOption{MaxErrors(3)}}, func(r Row) {
+			r.Put("bad")
+			r.Putter("x").Put("bad")
+		})
+	c.Assert(err, IsNil)
+	c.Check(report.Rows, Equals, 2)
+	c.Check(report.Errors, HasLen, 3)
+	c.Check(report.Truncated, Equals, 1)
+
+	var buf bytes.Buffer
+	c.Assert(report.WriteJSON(&buf), IsNil)
+	c.Check(buf.String(), Equals, `{"rows":2,"truncated":1,"errors":[`+
+		`{"row":1,"column":"","code":"","message":"bad"},`+
+		`{"row":1,"column":"x","code":"","message":"bad"},`+
+		`{"row":2,"column":"","code":"","message":"bad"}]}`+"\n")
+}
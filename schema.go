@@ -0,0 +1,188 @@
+package errstack
+
+import (
+	"encoding/json"
+)
+
+// Names of the schema definitions generated by SchemaDefinitions.
+const (
+	SchemaError         = "ErrstackError"
+	SchemaInternalError = "ErrstackInternalError"
+	SchemaRequestError  = "ErrstackRequestError"
+	SchemaWrappedError  = "ErrstackWrappedError"
+	SchemaJoinedError   = "ErrstackJoinedError"
+	SchemaFieldErrors   = "ErrstackFieldErrors"
+	SchemaFieldError    = "ErrstackFieldError"
+	SchemaCode          = "ErrstackCode"
+	SchemaBatch         = "ErrstackBatch"
+	SchemaRowsReport    = "ErrstackRowsReport"
+)
+
+// SchemaOptions configures schema generation.
+type SchemaOptions struct {
+	// RefPrefix is a prefix of definition references. Defaults to "#/definitions/".
+	RefPrefix string
+	// Codes lists error codes. If not empty, code properties are restricted to them.
+	// Use RegisteredCodes to list all registered codes.
+	Codes []string
+}
+
+type jsonSchema map[string]interface{}
+
+// SchemaDefinitions returns JSON Schema definitions of all response bodies produced
+// by this package, indexed by the definition name:
+//
+// + ErrstackError - body of any E.MarshalJSON
+// + ErrstackInternalError - sanitized infrastructure error
+// + ErrstackRequestError - request error with the message and the cause
+// + ErrstackWrappedError - layer of an error chain created with Wrap or WithMsg
+// + ErrstackJoinedError - errors combined with Join
+// + ErrstackFieldErrors - Builder errors indexed by the key
+// + ErrstackBatch - Batch summary
+// + ErrstackRowsReport - ValidateRows report
+func SchemaDefinitions(opts SchemaOptions) map[string]interface{} {
+	var prefix = opts.RefPrefix
+	if prefix == "" {
+		prefix = "#/definitions/"
+	}
+	var ref = func(name string) jsonSchema {
+		return jsonSchema{"$ref": prefix + name}
+	}
+	var str = jsonSchema{"type": "string"}
+	var code = jsonSchema{"type": "string"}
+	if len(opts.Codes) != 0 {
+		code["enum"] = opts.Codes
+	}
+	var cause = jsonSchema{"anyOf": []jsonSchema{str, ref(SchemaWrappedError), ref(SchemaError)}}
+
+	return map[string]interface{}{
+		SchemaError: jsonSchema{
+			"description": "Error response body.",
+			"anyOf": []jsonSchema{
+				ref(SchemaInternalError), ref(SchemaRequestError), ref(SchemaJoinedError),
+				ref(SchemaFieldErrors)},
+		},
+		SchemaInternalError: jsonSchema{
+			"description": "Infrastructure or domain error. Details are not exposed: the message is " +
+				"either the public message or starts with `Internal server error`. " +
+				"The object form is used when the error has a code or an incident ID.",
+			"anyOf": []jsonSchema{
+				str,
+				{
+					"type": "object",
+					"properties": jsonSchema{
+						"msg":      str,
+						"code":     ref(SchemaCode),
+						"incident": str,
+					},
+					"required": []string{"msg"},
+				},
+			},
+		},
+		SchemaRequestError: jsonSchema{
+			"description": "Request error. `err` contains the cause or field errors.",
+			"type":        "object",
+			"properties": jsonSchema{
+				"msg":      str,
+				"code":     ref(SchemaCode),
+				"incident": str,
+				"err":      jsonSchema{"anyOf": []jsonSchema{cause, ref(SchemaFieldErrors)}},
+			},
+		},
+		SchemaWrappedError: jsonSchema{
+			"description": "Layer of an error chain.",
+			"type":        "object",
+			"properties":  jsonSchema{"msg": str, "err": cause},
+			"required":    []string{"msg"},
+		},
+		SchemaJoinedError: jsonSchema{
+			"description": "Errors combined with Join.",
+			"type":        "object",
+			"properties": jsonSchema{
+				"errors":   jsonSchema{"type": "array", "items": ref(SchemaError)},
+				"msg":      str,
+				"code":     ref(SchemaCode),
+				"incident": str,
+			},
+			"required": []string{"errors"},
+		},
+		SchemaFieldErrors: jsonSchema{
+			"description": "Request errors indexed by the field key. Keys of forked builders " +
+				"are joined with `|` (Builder.Fork) or `:` (Putter.Fork).",
+			"type":                 "object",
+			"additionalProperties": ref(SchemaFieldError),
+		},
+		SchemaFieldError: jsonSchema{
+			"description": "Error of a field: a message, an error or a list of errors.",
+			"anyOf": []jsonSchema{
+				str,
+				{"type": "number"},
+				ref(SchemaError),
+				{"type": "array", "items": ref(SchemaFieldError)},
+			},
+		},
+		SchemaCode: code,
+		SchemaBatch: jsonSchema{
+			"description": "Result of a batch operation.",
+			"type":        "object",
+			"properties": jsonSchema{
+				"total":     jsonSchema{"type": "integer"},
+				"succeeded": jsonSchema{"type": "integer"},
+				"failed":    jsonSchema{"type": "integer"},
+				"truncated": jsonSchema{"type": "integer"},
+				"items": jsonSchema{
+					"type": "array",
+					"items": jsonSchema{
+						"type": "object",
+						"properties": jsonSchema{
+							"index":  jsonSchema{"type": "integer"},
+							"status": jsonSchema{"type": "integer"},
+							"err":    jsonSchema{"anyOf": []jsonSchema{ref(SchemaFieldErrors), ref(SchemaError)}},
+						},
+						"required": []string{"index", "status", "err"},
+					},
+				},
+			},
+			"required": []string{"total", "succeeded", "failed", "items"},
+		},
+		SchemaRowsReport: jsonSchema{
+			"description": "Report of a tabular data validation.",
+			"type":        "object",
+			"properties": jsonSchema{
+				"rows":      jsonSchema{"type": "integer"},
+				"truncated": jsonSchema{"type": "integer"},
+				"errors": jsonSchema{
+					"type": "array",
+					"items": jsonSchema{
+						"type": "object",
+						"properties": jsonSchema{
+							"row":     jsonSchema{"type": "integer"},
+							"column":  str,
+							"code":    jsonSchema{"anyOf": []jsonSchema{ref(SchemaCode), {"type": "string", "maxLength": 0}}},
+							"message": str,
+						},
+						"required": []string{"row", "column", "code", "message"},
+					},
+				},
+			},
+			"required": []string{"rows", "errors"},
+		},
+	}
+}
+
+// JSONSchema returns a JSON Schema (draft-07) document with SchemaDefinitions.
+func JSONSchema(opts SchemaOptions) ([]byte, error) {
+	return json.MarshalIndent(jsonSchema{
+		"$schema":     "http://json-schema.org/draft-07/schema#",
+		"definitions": SchemaDefinitions(opts),
+	}, "", "  ")
+}
+
+// OpenAPIComponents returns OpenAPI `components` object with SchemaDefinitions.
+// It can be merged into an OpenAPI document.
+func OpenAPIComponents(opts SchemaOptions) ([]byte, error) {
+	opts.RefPrefix = "#/components/schemas/"
+	return json.MarshalIndent(jsonSchema{
+		"components": jsonSchema{"schemas": SchemaDefinitions(opts)},
+	}, "", "  ")
+}
@@ -0,0 +1,204 @@
+package errstack
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+
+	. "gopkg.in/check.v1"
+)
+
+type SchemaSuite struct{}
+
+var refRe = regexp.MustCompile(`"\$ref": "([^"]*)"`)
+
+func checkRefs(c *C, doc []byte, prefix string, defs map[string]interface{}) {
+	refs := refRe.FindAllSubmatch(doc, -1)
+	c.Assert(len(refs) > 0, Equals, true)
+	for _, m := range refs {
+		name := string(m[1])
+		c.Assert(name[:len(prefix)], Equals, prefix)
+		_, ok := defs[name[len(prefix):]]
+		c.Check(ok, Equals, true, Commentf("undefined reference %s", name))
+	}
+}
+
+func (s *SchemaSuite) TestJSONSchema(c *C) {
+	doc, err := JSONSchema(SchemaOptions{Codes: []string{"user_not_found"}})
+	c.Assert(err, IsNil)
+	var v struct {
+		Definitions map[string]interface{}
+	}
+	c.Assert(json.Unmarshal(doc, &v), IsNil)
+	c.Check(v.Definitions[SchemaCode], DeepEquals,
+		map[string]interface{}{"type": "string", "enum": []interface{}{"user_not_found"}})
+	checkRefs(c, doc, "#/definitions/", v.Definitions)
+}
+
+func (s *SchemaSuite) TestOpenAPIComponents(c *C) {
+	doc, err := OpenAPIComponents(SchemaOptions{})
+	c.Assert(err, IsNil)
+	var v struct {
+		Components struct {
+			Schemas map[string]interface{}
+		}
+	}
+	c.Assert(json.Unmarshal(doc, &v), IsNil)
+	c.Check(v.Components.Schemas, HasLen, 10)
+	checkRefs(c, doc, "#/components/schemas/", v.Components.Schemas)
+}
+
+// validateSchema checks `v` (decoded JSON) against a subset of JSON Schema used by
+// SchemaDefinitions. It's stricter than JSON Schema: object properties which are not
+// listed in `properties` are rejected, so changes of the rendered shapes are detected.
+func validateSchema(defs map[string]interface{}, schema map[string]interface{}, v interface{}) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		name := ref[len("#/definitions/"):]
+		return validateSchema(defs, defs[name].(map[string]interface{}), v)
+	}
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		for _, s := range anyOf {
+			if validateSchema(defs, s.(map[string]interface{}), v) == nil {
+				return nil
+			}
+		}
+		return NewReqF("%v doesn't match any of %v", v, anyOf)
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		var found bool
+		for _, x := range enum {
+			found = found || x == v
+		}
+		if !found {
+			return NewReqF("%v is not one of %v", v, enum)
+		}
+	}
+	switch schema["type"] {
+	case "string":
+		s, ok := v.(string)
+		if !ok {
+			return NewReqF("%v is not a string", v)
+		}
+		if max, ok := schema["maxLength"].(float64); ok && len(s) > int(max) {
+			return NewReqF("%q is too long", s)
+		}
+	case "number":
+		if _, ok := v.(float64); !ok {
+			return NewReqF("%v is not a number", v)
+		}
+	case "integer":
+		if f, ok := v.(float64); !ok || f != float64(int64(f)) {
+			return NewReqF("%v is not an integer", v)
+		}
+	case "array":
+		l, ok := v.([]interface{})
+		if !ok {
+			return NewReqF("%v is not an array", v)
+		}
+		for _, x := range l {
+			if err := validateSchema(defs, schema["items"].(map[string]interface{}), x); err != nil {
+				return err
+			}
+		}
+	case "object":
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return NewReqF("%v is not an object", v)
+		}
+		props, _ := schema["properties"].(map[string]interface{})
+		for _, r := range asSlice(schema["required"]) {
+			if _, ok := m[r.(string)]; !ok {
+				return NewReqF("%v misses required property %s", v, r)
+			}
+		}
+		for k, x := range m {
+			s, ok := props[k].(map[string]interface{})
+			if !ok {
+				s, ok = schema["additionalProperties"].(map[string]interface{})
+			}
+			if !ok {
+				return NewReqF("%v has unknown property %s", v, k)
+			}
+			if err := validateSchema(defs, s, x); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func asSlice(v interface{}) []interface{} {
+	l, _ := v.([]interface{})
+	return l
+}
+
+func (s *SchemaSuite) TestRenderedShapes(c *C) {
+	doc, err := JSONSchema(SchemaOptions{})
+	c.Assert(err, IsNil)
+	var v struct {
+		Definitions map[string]interface{}
+	}
+	c.Assert(json.Unmarshal(doc, &v), IsNil)
+
+	b := NewBuilder(MaxErrors(3))
+	b.Put("email", "is required")
+	b.Fork("addr").Put("city", NewReq("unknown city"))
+	b.Put("tags", "too short")
+	b.Put("tags", "too long")
+
+	batch := NewBatch(3)
+	batch.Item(0).Put("name", "too short")
+	batch.Fail(1, NewIO("db"))
+	batch.Fail(2, errors.New("raw"))
+
+	rows, err := ValidateRows(csv.NewReader(strings.NewReader("name\n\nok\n")), RowsConfig{Header: true},
+		func(r Row) { r.Putter("name").Put(NewReq("is required").WithCode("required")) })
+	c.Assert(err, IsNil)
+
+	var cases = []struct {
+		def   string
+		value interface{}
+	}{
+		{SchemaInternalError, errors.New("x")},
+		{SchemaInternalError, NewIO("db is down")},
+		{SchemaInternalError, NewIO("db is down").WithCode("db").WithPublicMsg("try again")},
+		{SchemaRequestError, WrapAsReq(errors.New("cause"), "one").WithMsg("two").WithCode("bad")},
+		{SchemaRequestError, WrapAsReq(NewReqDetails("k", "v", "invalid"), "two")},
+		{SchemaRequestError, WrapAsReq(NewIO("db"), "bad")},
+		{SchemaJoinedError, Join(NewReq("a"), NewIO("b"), errors.New("c"))},
+		{SchemaFieldErrors, b.ToReqErr()},
+		{SchemaBatch, batch},
+		{SchemaRowsReport, rows},
+	}
+	for _, tc := range cases {
+		var value = tc.value
+		if err, ok := value.(error); ok {
+			value = sanitize(err)
+		}
+		data, err := json.Marshal(value)
+		c.Assert(err, IsNil)
+		var decoded interface{}
+		c.Assert(json.Unmarshal(data, &decoded), IsNil)
+		def := v.Definitions[tc.def].(map[string]interface{})
+		c.Check(validateSchema(v.Definitions, def, decoded), IsNil, Commentf("%s: %s", tc.def, data))
+		if tc.def != SchemaBatch && tc.def != SchemaRowsReport {
+			errDef := v.Definitions[SchemaError].(map[string]interface{})
+			c.Check(validateSchema(v.Definitions, errDef, decoded), IsNil, Commentf("%s", data))
+		}
+	}
+
+	// the validator detects shape changes
+	for def, value := range map[string]string{
+		SchemaInternalError: `{"msg":"x","details":{}}`,
+		SchemaJoinedError:   `{"errors":"x"}`,
+		SchemaBatch:         `{"total":1,"succeeded":1,"failed":0}`,
+		SchemaRowsReport:    `{"rows":1,"errors":[{"row":1.5,"column":"","code":"","message":""}]}`,
+	} {
+		var decoded interface{}
+		c.Assert(json.Unmarshal([]byte(value), &decoded), IsNil)
+		c.Check(validateSchema(v.Definitions, v.Definitions[def].(map[string]interface{}), decoded), NotNil,
+			Commentf("%s: %s", def, value))
+	}
+}
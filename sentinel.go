@@ -0,0 +1,106 @@
+package errstack
+
+import "fmt"
+
+// Sentinel defines a class of errors with a kind, a code and a message template.
+// Errors created by a Sentinel carry the stacktrace and can be matched with `errors.Is`.
+// Example:
+//
+//	var ErrUserNotFound = Define(NotExist, "user_not_found", "user %s not found")
+//
+//	func GetUser(id string) (User, error) {
+//		...
+//		return u, ErrUserNotFound.Wrap(err, id)
+//	}
+//
+//	if errors.Is(err, ErrUserNotFound) { ... }
+type Sentinel struct {
+	kind   Kind
+	code   string
+	format string
+	status int
+	public bool
+}
+
+// Define creates a new Sentinel. `format` is used to create error messages with the
+// arguments passed to the New and Wrap methods.
+// The code is registered (see RegisterCode). Define panics if the code is already
+// registered, so it should be used to initialize package variables.
+func Define(kind Kind, code string, format string) *Sentinel {
+	s := &Sentinel{kind: kind, code: code, format: format}
+	if err := registerSentinel(s); err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// WithStatus sets the HTTP status code of the Sentinel errors, overriding the
+// status derived from the kind. It returns the Sentinel to be used with Define:
+//
+//	var ErrQuota = Define(Permission, "quota_exceeded", "quota exceeded").WithStatus(429)
+func (s *Sentinel) WithStatus(status int) *Sentinel {
+	s.status = status
+	return s
+}
+
+// Error implements error interface. It returns the Sentinel code.
+func (s *Sentinel) Error() string {
+	return s.code
+}
+
+// Kind returns kind of the Sentinel errors.
+func (s *Sentinel) Kind() Kind {
+	return s.kind
+}
+
+// Code returns code of the Sentinel errors.
+func (s *Sentinel) Code() string {
+	return s.code
+}
+
+// Public marks the Sentinel messages as public: they are used as public messages
+// of the created errors (see E.WithPublicMsg). It returns the Sentinel to be used
+// with Define.
+func (s *Sentinel) Public() *Sentinel {
+	s.public = true
+	return s
+}
+
+// IsPublic reports whether the Sentinel messages are public.
+func (s *Sentinel) IsPublic() bool {
+	return s.public
+}
+
+// Format returns the message template of the Sentinel errors.
+func (s *Sentinel) Format() string {
+	return s.format
+}
+
+// Status returns HTTP status code set with WithStatus (0 if not set).
+func (s *Sentinel) Status() int {
+	return s.status
+}
+
+// New creates new error using the Sentinel message template with `args`.
+func (s *Sentinel) New(args ...interface{}) E {
+	return s.newErr(nil, args, 1)
+}
+
+// Wrap creates new error wrapping `err` using the Sentinel message template with `args`.
+// If `err` is nil, nil is returned.
+func (s *Sentinel) Wrap(err error, args ...interface{}) E {
+	if err == nil {
+		return nil
+	}
+	return s.newErr(err, args, 1)
+}
+
+func (s *Sentinel) newErr(err error, args []interface{}, skip int) E {
+	e := newErr(err, fmt.Sprintf(s.format, args...), s.kind, skip+1)
+	e.def = s
+	e.code = s.code
+	if s.public {
+		e.publicMsg = e.msg
+	}
+	return e
+}
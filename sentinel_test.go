@@ -0,0 +1,61 @@
+package errstack
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/robert-zaremba/checkers"
+	. "gopkg.in/check.v1"
+)
+
+type SentinelSuite struct{}
+
+var (
+	errTestNotFound = Define(NotExist, "test_not_found", "user %s not found")
+	errTestOther    = Define(NotExist, "test_other", "other")
+	errTestQuota    = Define(Permission, "test_quota", "quota exceeded").WithStatus(429)
+)
+
+func (s *SentinelSuite) TestNew(c *C) {
+	err := errTestNotFound.New("john")
+	c.Check(err.Error(), Equals, "user john not found")
+	c.Check(err.Kind(), Equals, NotExist)
+	c.Check(err.StatusCode(), Equals, 400)
+	c.Check(len(err.Stacktrace()) > 0, IsTrue)
+	c.Check(errors.Is(err, errTestNotFound), IsTrue)
+	c.Check(errors.Is(err, errTestOther), IsFalse)
+	c.Check(errTestNotFound.New("a") == errTestNotFound.New("a"), IsFalse)
+}
+
+func (s *SentinelSuite) TestWrap(c *C) {
+	c.Check(errTestNotFound.Wrap(nil, "john"), IsNil)
+
+	cause := errors.New("no rows")
+	err := errTestNotFound.Wrap(cause, "john")
+	c.Check(err.Error(), Equals, "user john not found [no rows]")
+	c.Check(errors.Is(err, errTestNotFound), IsTrue)
+	c.Check(errors.Is(err, cause), IsTrue)
+
+	// identity is kept through other layers
+	werr := fmt.Errorf("handler: %w", WrapAsIO(err, "loading"))
+	c.Check(errors.Is(werr, errTestNotFound), IsTrue)
+	c.Check(errors.Is(err.WithMsg("more"), errTestNotFound), IsTrue)
+}
+
+func (s *SentinelSuite) TestWithStatus(c *C) {
+	c.Check(errTestQuota.New().StatusCode(), Equals, 429)
+	c.Check(errTestQuota.Wrap(NewIO("db")).StatusCode(), Equals, 429)
+	c.Check(errTestNotFound.New("x").StatusCode(), Equals, 400)
+}
+
+func (s *SentinelSuite) TestKind(c *C) {
+	c.Check(NotExist.String(), Equals, "NotExist")
+	c.Check(Kind(100).String(), Equals, "Kind(100)")
+	k, err := ParseKind("Domain")
+	c.Check(err, IsNil)
+	c.Check(k, Equals, Domain)
+	_, err = ParseKind("domain")
+	c.Check(err, ErrorMatches, `unknown error kind "domain"`)
+	c.Check(Request.IsReq(), IsTrue)
+	c.Check(IO.IsReq(), IsFalse)
+}
@@ -0,0 +1,23 @@
+package errstack
+
+import "sync/atomic"
+
+// setting is a package level setting. It can be changed while errors are created
+// and rendered in other goroutines.
+type setting[T any] struct {
+	p atomic.Pointer[T]
+}
+
+func newSetting[T any](v T) *setting[T] {
+	var s = &setting[T]{}
+	s.set(v)
+	return s
+}
+
+func (s *setting[T]) get() T {
+	return *s.p.Load()
+}
+
+func (s *setting[T]) set(v T) {
+	s.p.Store(&v)
+}
@@ -8,20 +8,19 @@ func wrapSimpleRequest(e error, message string, skip int) E {
 	if e == nil {
 		return nil
 	}
-	switch ee := e.(type) {
-	case *request:
-		return ee.WithMsg(message)
-	case errstack:
-		if ee.kind == Request {
-			return ee.WithMsg(message)
-		}
+	if r, ok := e.(*request); ok {
+		return r.WithMsg(message)
+	}
+	if es, ok := asKind(e, Request); ok {
+		return es.WithMsg(message)
 	}
 	return newErr(e, message, Request, skip+1)
 }
 
-// NewReqF creates request error from format
+// NewReqF creates request error from format.
+// It supports the `%w` verb, see Errorf.
 func NewReqF(f string, a ...interface{}) E {
-	return newErr(nil, fmt.Sprintf(f, a...), Request, 1)
+	return errorf(Request, 1, f, a)
 }
 
 // NewReq creates request error from string
@@ -0,0 +1,86 @@
+package errstack
+
+import (
+	"github.com/facebookgo/stack"
+)
+
+// TransitionPolicy decides if an error of the kind `from` can be wrapped into an
+// error of the kind `to`. When the transition is denied, the new error keeps the
+// `from` kind, but IsKind reports both kinds. Use Reclassify to change the kind
+// regardless of the policy.
+// Transitions from and to the Other kind are always allowed.
+type TransitionPolicy func(from, to Kind) bool
+
+// DefaultTransitionPolicy denies downgrading infrastructure errors into request
+// errors, so a failure of a dependency is not reported as a client error.
+func DefaultTransitionPolicy(from, to Kind) bool {
+	return from.IsReq() || !to.IsReq()
+}
+
+// AllowAllTransitions allows all transitions.
+func AllowAllTransitions(from, to Kind) bool {
+	return true
+}
+
+var transitionPolicy = newSetting[TransitionPolicy](DefaultTransitionPolicy)
+
+// SetTransitionPolicy sets the policy checked whenever an error is wrapped with a new
+// kind. nil restores DefaultTransitionPolicy. Kinds of existing errors don't change.
+func SetTransitionPolicy(p TransitionPolicy) {
+	if p == nil {
+		p = DefaultTransitionPolicy
+	}
+	transitionPolicy.set(p)
+}
+
+// DebugLogger is used to report suspicious usage of the package.
+type DebugLogger interface {
+	Debug(msg string, ctx ...interface{})
+}
+
+var debugLogger = newSetting[DebugLogger](nil)
+
+// SetDebugLogger sets the logger for debug warnings, eg: denied kind transitions.
+// nil disables the warnings. The logger is called from goroutines creating errors,
+// so it must be safe for concurrent use.
+func SetDebugLogger(l DebugLogger) {
+	debugLogger.set(l)
+}
+
+// transitionKind returns the kind of an error wrapping `cause` when the `to` kind
+// is requested.
+func transitionKind(cause error, to Kind, skip int) Kind {
+	from := sourceKind(cause)
+	if from == Other || to == Other || from == to || transitionPolicy.get()(from, to) {
+		return to
+	}
+	if l := debugLogger.get(); l != nil {
+		l.Debug("errstack: denied kind transition, use Reclassify to change the kind",
+			"from", from, "to", to, "caller", stack.Caller(skip+1))
+	}
+	return from
+}
+
+// sourceKind returns the kind of the first error with a kind in the cause tree,
+// so layers added with fmt.Errorf don't hide the original kind.
+func sourceKind(err error) Kind {
+	var kind = Other
+	FindCause(err, func(e error) bool {
+		if x, ok := e.(E); ok {
+			kind = x.Kind()
+		}
+		return kind != Other
+	})
+	return kind
+}
+
+// Reclassify wraps `err` into a new error of the `kind`, regardless of the
+// TransitionPolicy. It should be used when the change of the kind is intended,
+// eg: a missing record in a database is a client error.
+// If `err` is nil, nil is returned.
+func Reclassify(err error, kind Kind, msg string) E {
+	if err == nil {
+		return nil
+	}
+	return newErrOfKind(err, msg, kind, kind, 1)
+}
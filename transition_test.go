@@ -0,0 +1,75 @@
+package errstack
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/robert-zaremba/checkers"
+	. "gopkg.in/check.v1"
+)
+
+type TransitionSuite struct{}
+
+func (s *TransitionSuite) TearDownTest(c *C) {
+	SetTransitionPolicy(nil)
+	SetDebugLogger(nil)
+}
+
+type testDebugLogger []string
+
+func (l *testDebugLogger) Debug(msg string, ctx ...interface{}) {
+	*l = append(*l, fmt.Sprint(msg, ctx[:4]))
+}
+
+func (s *TransitionSuite) TestDenied(c *C) {
+	var logger testDebugLogger
+	SetDebugLogger(&logger)
+
+	err := WrapAsReq(NewIO("db is down"), "can't find user")
+	c.Check(err.Kind(), Equals, IO)
+	c.Check(err.IsReq(), IsFalse)
+	c.Check(err.StatusCode(), Equals, 500)
+	c.Check(IsKind(IO, err), IsTrue)
+	c.Check(IsKind(Request, err), IsTrue)
+	c.Check(IsKind(Domain, err), IsFalse)
+	c.Check(IsKind(Request, err.WithMsg("wrapped")), IsTrue)
+	c.Check(logger, DeepEquals, testDebugLogger{
+		"errstack: denied kind transition, use Reclassify to change the kind[from IO to Request]"})
+
+	// kinds are found through layers which are not E
+	err = WrapAsReq(fmt.Errorf("lookup: %w", NewIO("db is down")), "bad")
+	c.Check(err.Kind(), Equals, IO)
+	c.Check(err.StatusCode(), Equals, 500)
+	c.Check(IsKind(Request, err), IsTrue)
+	c.Check(WrapAsReq(Join(errors.New("x"), NewDomain("y")), "bad").Kind(), Equals, Domain)
+	c.Check(logger, HasLen, 3)
+
+	err = WrapAsReq(NewIO("db is down"), "can't find user")
+	data, err2 := err.MarshalJSON()
+	c.Assert(err2, IsNil)
+	c.Check(string(data), Equals, `{"incident":"42","msg":"Internal server error: can't find user"}`)
+}
+
+func (s *TransitionSuite) TestAllowed(c *C) {
+	var logger testDebugLogger
+	SetDebugLogger(&logger)
+
+	c.Check(WrapAsIO(NewReq("bad"), "x").Kind(), Equals, IO)
+	c.Check(Wrap(NewIO("db"), Other, "x").Kind(), Equals, Other)
+	c.Check(New(Permission, "x").Kind(), Equals, Permission)
+	c.Check(logger, HasLen, 0)
+
+	err := Reclassify(NewIO("no rows"), NotExist, "user not found")
+	c.Check(err.Kind(), Equals, NotExist)
+	c.Check(IsKind(IO, err), IsFalse)
+	c.Check(Reclassify(nil, NotExist, "x"), IsNil)
+	c.Check(logger, HasLen, 0)
+
+	SetTransitionPolicy(AllowAllTransitions)
+	c.Check(WrapAsReq(NewIO("db"), "x").Kind(), Equals, Request)
+
+	SetTransitionPolicy(func(from, to Kind) bool { return to != Domain })
+	c.Check(WrapAsDomain(NewIO("db"), "x").Kind(), Equals, IO)
+	c.Check(WrapAsIO(NewDomain("x"), "db").Kind(), Equals, IO)
+	c.Check(logger, HasLen, 1)
+}
@@ -7,7 +7,7 @@ import (
 )
 
 // UntilFirst is a struct to easily chain a sequence of operations
-// until a first error arise
+// until a first error arise. Use Group to run operations concurrently.
 type UntilFirst struct {
 	Err E
 }
@@ -49,9 +49,14 @@ func CallAndLog(l Logger, f func() error) {
 	Log(l, f())
 }
 
-// Log logs error if it's not nil
+// Log logs error if it's not nil. The incident ID (if any) is added to the context.
 func Log(l Logger, err error) {
-	if err != nil {
+	if err == nil {
+		return
+	}
+	if id := IncidentID(err); id != "" {
+		l.Error("Unhandled error", err, "incident", id)
+	} else {
 		l.Error("Unhandled error", err)
 	}
 }